@@ -0,0 +1,166 @@
+// Command gendeps generates the files for the external packages supported on the Go track
+// from the list in external-packages/packages.json:
+//
+//   - external-packages/deps.go imports the packages, so they are downloaded when the
+//     docker image is built.
+//   - testrunner/supported_modules.json lists the modules of the packages with their
+//     versions, it is embedded in the test runner to check the imports of solutions.
+//
+// Run it from the root of the repository after changing the list:
+//
+//	go run ./cmd/gendeps
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"golang.org/x/mod/modfile"
+)
+
+// supportedPackage is an entry of packages.json.
+type supportedPackage struct {
+	Package string `json:"package"`
+	// Comment is added to the import in deps.go.
+	Comment string `json:"comment,omitempty"`
+}
+
+// manifest is the content of supported_modules.json, see testrunner.supportedModules.
+type manifest struct {
+	Go      string           `json:"go"`
+	Modules []manifestModule `json:"modules"`
+}
+
+type manifestModule struct {
+	Path     string   `json:"path"`
+	Version  string   `json:"version"`
+	Packages []string `json:"packages"`
+}
+
+var depsTemplate = template.Must(template.New("deps.go").Parse(`// Code generated by gendeps from packages.json; DO NOT EDIT.
+
+package external_packages
+
+// This file imports the dependencies we want to support on the Go track.
+// To add or remove a supported dependency, add or remove a package in packages.json
+// and run 'go run ./cmd/gendeps' in the root of the repository.
+
+// Note that some packages are part of a module, in which case the whole
+// module will be downloaded as a dependency.
+// e.g "golang.org/x/exp/constraints" is a package that is part of the
+// "golang.org/x/exp" module. Importing "golang.org/x/exp/constraints"
+// makes the whole "golang.org/x/exp/" module be downloaded and referenced
+// in the go.mod file.
+// This means that if you want to add a module as a dependency
+// that is not itself a package, importing any of its sub-packages
+// should suffice.
+
+import (
+{{- range .}}
+	_ "{{.Package}}"{{if .Comment}} // {{.Comment}}{{end}}
+{{- end}}
+)
+`))
+
+func main() {
+	dir := flag.String("dir", "external-packages", "directory of the external packages module")
+	manifestPath := flag.String("manifest", filepath.Join("testrunner", "supported_modules.json"), "path of the generated manifest")
+	tidy := flag.Bool("tidy", true, "run 'go mod tidy' in the external packages module, needs network access")
+	flag.Parse()
+
+	content, err := os.ReadFile(filepath.Join(*dir, "packages.json"))
+	if err != nil {
+		log.Fatalf("failed to read the supported packages: %s", err)
+	}
+	var packages []supportedPackage
+	if err := json.Unmarshal(content, &packages); err != nil {
+		log.Fatalf("failed to parse packages.json: %s", err)
+	}
+
+	deps, err := renderDeps(packages)
+	if err != nil {
+		log.Fatalf("failed to generate deps.go: %s", err)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, "deps.go"), deps, 0644); err != nil {
+		log.Fatalf("failed to write deps.go: %s", err)
+	}
+
+	if *tidy {
+		cmd := exec.Command("go", "mod", "tidy")
+		cmd.Dir = *dir
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			log.Fatalf("go mod tidy failed: %s", err)
+		}
+	}
+
+	goModPath := filepath.Join(*dir, "go.mod")
+	goModContent, err := os.ReadFile(goModPath)
+	if err != nil {
+		log.Fatalf("failed to read go.mod: %s", err)
+	}
+	goMod, err := modfile.Parse(goModPath, goModContent, nil)
+	if err != nil {
+		log.Fatalf("failed to parse go.mod: %s", err)
+	}
+	m, err := buildManifest(packages, goMod)
+	if err != nil {
+		log.Fatal(err)
+	}
+	bts, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		log.Fatalf("failed to marshal the manifest: %s", err)
+	}
+	if err := os.WriteFile(*manifestPath, append(bts, '\n'), 0644); err != nil {
+		log.Fatalf("failed to write the manifest: %s", err)
+	}
+}
+
+// renderDeps returns the formatted content of deps.go.
+func renderDeps(packages []supportedPackage) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := depsTemplate.Execute(&buf, packages); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// buildManifest assigns the packages to the modules required by the go.mod file.
+// Every package must be provided by a direct requirement.
+func buildManifest(packages []supportedPackage, goMod *modfile.File) (manifest, error) {
+	m := manifest{Modules: []manifestModule{}}
+	if goMod.Go != nil {
+		m.Go = goMod.Go.Version
+	}
+	indexByPath := map[string]int{}
+	for _, pkg := range packages {
+		var provider *modfile.Require
+		for _, req := range goMod.Require {
+			inModule := pkg.Package == req.Mod.Path || strings.HasPrefix(pkg.Package, req.Mod.Path+"/")
+			if !req.Indirect && inModule && (provider == nil || len(req.Mod.Path) > len(provider.Mod.Path)) {
+				provider = req
+			}
+		}
+		if provider == nil {
+			return m, fmt.Errorf("no module in go.mod provides package %s, run 'go mod tidy'", pkg.Package)
+		}
+		idx, ok := indexByPath[provider.Mod.Path]
+		if !ok {
+			m.Modules = append(m.Modules, manifestModule{Path: provider.Mod.Path, Version: provider.Mod.Version})
+			idx = len(m.Modules) - 1
+			indexByPath[provider.Mod.Path] = idx
+		}
+		m.Modules[idx].Packages = append(m.Modules[idx].Packages, pkg.Package)
+	}
+	return m, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+func TestRenderDeps(t *testing.T) {
+	deps, err := renderDeps([]supportedPackage{
+		{Package: "golang.org/x/exp/constraints", Comment: "package of module golang.org/x/exp"},
+		{Package: "golang.org/x/text"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(deps), "// Code generated by gendeps from packages.json; DO NOT EDIT.\n\npackage external_packages\n")
+	assert.Contains(t, string(deps), "import (\n"+
+		"\t_ \"golang.org/x/exp/constraints\" // package of module golang.org/x/exp\n"+
+		"\t_ \"golang.org/x/text\"\n"+
+		")\n")
+}
+
+func TestBuildManifest(t *testing.T) {
+	goMod, err := modfile.Parse("go.mod", []byte("module external_packages\n\ngo 1.26\n\nrequire (\n"+
+		"\tgolang.org/x/exp v0.1.0\n"+
+		"\tgolang.org/x/text v0.7.0\n"+
+		"\tgolang.org/x/sys v0.5.0 // indirect\n"+
+		")\n"), nil)
+	require.NoError(t, err)
+
+	m, err := buildManifest([]supportedPackage{
+		{Package: "golang.org/x/exp/constraints"},
+		{Package: "golang.org/x/text"},
+		{Package: "golang.org/x/exp/slices"},
+	}, goMod)
+	require.NoError(t, err)
+	assert.Equal(t, manifest{
+		Go: "1.26",
+		Modules: []manifestModule{
+			{Path: "golang.org/x/exp", Version: "v0.1.0", Packages: []string{"golang.org/x/exp/constraints", "golang.org/x/exp/slices"}},
+			{Path: "golang.org/x/text", Version: "v0.7.0", Packages: []string{"golang.org/x/text"}},
+		},
+	}, m)
+
+	_, err = buildManifest([]supportedPackage{{Package: "golang.org/x/sys/unix"}}, goMod)
+	assert.EqualError(t, err, "no module in go.mod provides package golang.org/x/sys/unix, run 'go mod tidy'")
+}
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	root := filepath.Join("..", "..")
+	dir := filepath.Join(root, "external-packages")
+
+	content, err := os.ReadFile(filepath.Join(dir, "packages.json"))
+	require.NoError(t, err)
+	var packages []supportedPackage
+	require.NoError(t, json.Unmarshal(content, &packages))
+
+	deps, err := renderDeps(packages)
+	require.NoError(t, err)
+	committedDeps, err := os.ReadFile(filepath.Join(dir, "deps.go"))
+	require.NoError(t, err)
+	assert.Equal(t, string(committedDeps), string(deps), "deps.go is out of date, run 'go run ./cmd/gendeps'")
+
+	goModPath := filepath.Join(dir, "go.mod")
+	goModContent, err := os.ReadFile(goModPath)
+	require.NoError(t, err)
+	goMod, err := modfile.Parse(goModPath, goModContent, nil)
+	require.NoError(t, err)
+	m, err := buildManifest(packages, goMod)
+	require.NoError(t, err)
+	bts, err := json.MarshalIndent(m, "", "  ")
+	require.NoError(t, err)
+	committedManifest, err := os.ReadFile(filepath.Join(root, "testrunner", "supported_modules.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(committedManifest), string(append(bts, '\n')), "supported_modules.json is out of date, run 'go run ./cmd/gendeps'")
+}
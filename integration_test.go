@@ -2,13 +2,12 @@ package main
 
 import (
 	"bytes"
-	"go/build"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
-	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -39,17 +38,29 @@ var regexReplacements = []struct {
 		regexp:     regexp.MustCompile(`goroutine [0-9]+`),
 		replaceStr: "goroutine x",
 	},
+	{
+		// Durations in milliseconds
+		regexp:     regexp.MustCompile(`"duration_ms": [0-9]+`),
+		replaceStr: `"duration_ms": 0`,
+	},
 	{
 		// Line number
 		regexp:     regexp.MustCompile(`\.go:[0-9]+(:[0-9]+)?`),
 		replaceStr: ".go",
 	},
+	{
+		// Version of the installed toolchain
+		regexp:     regexp.MustCompile(`ran with (Go |go)[0-9]+\.[0-9]+(\.[0-9]+)?`),
+		replaceStr: "ran with ${1}x",
+	},
 }
 
 func TestIntegration(t *testing.T) {
 	tests := []struct {
 		inputDir string
 		expected string
+		// goos restricts the test to an operating system, e.g. for features only supported on linux.
+		goos string
 	}{
 		{
 			// This test case covers the case the code under test does not compile,
@@ -63,6 +74,14 @@ func TestIntegration(t *testing.T) {
 			inputDir: filepath.Join("testrunner", "testdata", "practice", "missing_func"),
 			expected: filepath.Join("testrunner", "testdata", "expected", "missing_func.json"),
 		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "misnamed_func"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "misnamed_func.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "signature_mismatch"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "signature_mismatch.json"),
+		},
 		{
 			inputDir: filepath.Join("testrunner", "testdata", "practice", "broken_import"),
 			expected: filepath.Join("testrunner", "testdata", "expected", "broken_import.json"),
@@ -75,6 +94,51 @@ func TestIntegration(t *testing.T) {
 			inputDir: filepath.Join("testrunner", "testdata", "practice", "pkg_level_error"),
 			expected: filepath.Join("testrunner", "testdata", "expected", "pkg_level_error.json"),
 		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "single_erroring_test"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "single_erroring_test.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "test_main_panic"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "test_main_panic.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "build_tags"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "build_tags.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "timeout"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "timeout.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "memory_limit"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "memory_limit.json"),
+			goos:     "linux",
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "vet_warnings"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "vet_warnings.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "multi_package"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "multi_package.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "multi_package_task_ids"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "multi_package_task_ids.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "unsupported_package"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "unsupported_package.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "missing_go_mod"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "missing_go_mod.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "newer_go_version"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "newer_go_version.json"),
+		},
 		{
 			inputDir: filepath.Join("testrunner", "testdata", "practice", "separate_cases_file"),
 			expected: filepath.Join("testrunner", "testdata", "expected", "separate_cases_file.json"),
@@ -83,6 +147,14 @@ func TestIntegration(t *testing.T) {
 			inputDir: filepath.Join("testrunner", "testdata", "practice", "failing"),
 			expected: filepath.Join("testrunner", "testdata", "expected", "failing.json"),
 		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "skipped_tests"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "skipped_tests.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "practice", "hidden_skipped_tests"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "hidden_skipped_tests.json"),
+		},
 		{
 			inputDir: filepath.Join("testrunner", "testdata", "concept", "auto_assigned_task_ids"),
 			expected: filepath.Join("testrunner", "testdata", "expected", "auto_assigned_task_ids.json"),
@@ -95,6 +167,14 @@ func TestIntegration(t *testing.T) {
 			inputDir: filepath.Join("testrunner", "testdata", "concept", "missing_task_ids"),
 			expected: filepath.Join("testrunner", "testdata", "expected", "missing_task_ids.json"),
 		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "concept", "strict_task_ids"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "strict_task_ids.json"),
+		},
+		{
+			inputDir: filepath.Join("testrunner", "testdata", "concept", "subtest_task_ids"),
+			expected: filepath.Join("testrunner", "testdata", "expected", "subtest_task_ids.json"),
+		},
 		{
 			inputDir: filepath.Join("testrunner", "testdata", "concept", "non_executed_tests"),
 			expected: filepath.Join("testrunner", "testdata", "expected", "non_executed_tests.json"),
@@ -104,18 +184,14 @@ func TestIntegration(t *testing.T) {
 	goExe, err := exec.LookPath("go")
 	require.NoError(t, err, "failed to find go executable")
 
-	goRoot := os.Getenv("GOROOT")
-	if goRoot == "" {
-		goRoot = build.Default.GOROOT
-	}
-
-	currentDir, err := os.Getwd()
-	require.NoError(t, err, "failed to determine current directory")
-
 	for _, tt := range tests {
 		t.Run(tt.inputDir, func(t *testing.T) {
+			if tt.goos != "" && tt.goos != runtime.GOOS {
+				t.Skipf("only supported on %s", tt.goos)
+			}
 			err := os.RemoveAll("outdir")
 			require.NoError(t, err, "failed to clean up output directory")
+			solutionFiles := readSolutionFiles(t, tt.inputDir)
 
 			var stdout, stderr bytes.Buffer
 			cmd := &exec.Cmd{
@@ -130,51 +206,37 @@ func TestIntegration(t *testing.T) {
 			resultBytes, err := os.ReadFile(filepath.Join("outdir", "results.json"))
 			require.NoError(t, err, "failed to read results")
 
-			result := sanitizeResult(string(resultBytes), []string{goExe, currentDir, goRoot})
+			result := sanitizeResult(string(resultBytes))
 
 			expected, err := os.ReadFile(tt.expected)
 			require.NoError(t, err, "failed to read expected result file")
 
 			assert.JSONEq(t, string(expected), result)
+			assert.Equal(t, solutionFiles, readSolutionFiles(t, tt.inputDir), "the solution directory was modified")
 		})
 	}
 }
 
-func sanitizeResult(s string, paths []string) string {
-	result := s
-
-	for _, p := range pathVariations(paths) {
-		result = strings.ReplaceAll(result, p, "PATH_PLACEHOLDER")
-	}
-
-	if runtime.GOOS == "windows" {
-		result = strings.ReplaceAll(result, `\n.//`, `\n./`)
-		result = strings.ReplaceAll(result, `\n.\\`, `\n./`)
-		result = strings.ReplaceAll(result, `\n.\`, `\n./`)
-	}
+// readSolutionFiles returns the content of all files in the solution directory by path.
+func readSolutionFiles(t *testing.T, input_dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.WalkDir(input_dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		files[path] = string(content)
+		return err
+	})
+	require.NoError(t, err, "failed to read the solution directory")
+	return files
+}
 
+func sanitizeResult(s string) string {
+	result := s
 	for _, replacement := range regexReplacements {
 		result = replacement.regexp.ReplaceAllString(result, replacement.replaceStr)
 	}
-
-	return result
-}
-
-func pathVariations(paths []string) []string {
-	result := []string{}
-	for _, p := range paths {
-		normalizedPath := filepath.ToSlash(p)
-		result = append(result, normalizedPath)
-
-		if runtime.GOOS == "windows" {
-			// On windows, the paths that are included in the test results can have
-			// various formats. We try to include all variants here so we catch
-			// everything when we do the replace later.
-			result = append(result, strings.ReplaceAll(normalizedPath, "/", "//"))
-			result = append(result, strings.ReplaceAll(normalizedPath, "/", `\`))
-			result = append(result, strings.ReplaceAll(normalizedPath, "/", `\\`))
-		}
-	}
-
 	return result
 }
@@ -1,26 +1,73 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/exercism/go-test-runner/testrunner"
 )
 
+const (
+	printConfigCommand = "print-config"
+	flakyCheckCommand  = "flaky-check"
+	mutateCommand      = "mutate"
+)
+
 func main() {
-	if len(os.Args) != 3 {
-		log.Fatal("usage: go-test-runner input_dir output_dir")
+	if len(os.Args) > 1 && os.Args[1] == testrunner.LimitMemoryCommand {
+		// The test runner is invoked by `go test -exec` to run the test binary.
+		testrunner.ExecWithMemoryLimit(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == printConfigCommand {
+		flags := newRunnerFlags("go-test-runner "+printConfigCommand, "usage: go-test-runner print-config [flags] input_dir")
+		flags.parse(os.Args[2:])
+		if flags.NArg() != 1 {
+			log.Fatal(flags.usage)
+		}
+		fmt.Println(string(testrunner.PrintConfig(flags.Arg(0), flags.options().Config)))
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == flakyCheckCommand {
+		flags := newRunnerFlags("go-test-runner "+flakyCheckCommand, "usage: go-test-runner flaky-check [flags] input_dir")
+		runs := flags.Int("runs", 6, "number of times the tests are run")
+		flags.parse(os.Args[2:])
+		if flags.NArg() != 1 || *runs < 1 {
+			log.Fatal(flags.usage)
+		}
+		fmt.Println(string(testrunner.CheckFlakiness(flags.Arg(0), flags.options(), *runs)))
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == mutateCommand {
+		flags := newRunnerFlags("go-test-runner "+mutateCommand, "usage: go-test-runner mutate [flags] input_dir")
+		flags.parse(os.Args[2:])
+		if flags.NArg() != 1 {
+			log.Fatal(flags.usage)
+		}
+		fmt.Println(string(testrunner.Mutate(flags.Arg(0), flags.options())))
+		return
 	}
-	input_dir := os.Args[1]
-	output_dir := os.Args[2]
+
+	flags := newRunnerFlags("go-test-runner", "usage: go-test-runner [flags] input_dir output_dir")
+	flags.parse(os.Args[1:])
+	if flags.NArg() != 2 {
+		log.Fatal(flags.usage)
+	}
+	input_dir := flags.Arg(0)
+	output_dir := flags.Arg(1)
 	msg, ok := checkArgs(input_dir, output_dir)
 	if !ok {
 		log.Fatal(msg)
 	}
 
-	report := testrunner.Execute(input_dir)
+	report := testrunner.Execute(input_dir, flags.options())
 	results := filepath.Join(output_dir, "results.json")
 	err := os.WriteFile(results, report, 0644)
 	if err != nil {
@@ -28,6 +75,80 @@ func main() {
 	}
 }
 
+// runnerFlags contains the flags shared by the commands of the test runner.
+type runnerFlags struct {
+	*flag.FlagSet
+	usage       string
+	keepSkipped bool
+	configFile  string
+	settings    []string
+	limits      testrunner.OutputLimits
+}
+
+func newRunnerFlags(name string, usage string) *runnerFlags {
+	f := &runnerFlags{
+		FlagSet: flag.NewFlagSet(name, flag.ExitOnError),
+		usage:   usage,
+		limits:  testrunner.DefaultOutputLimits,
+	}
+	f.BoolVar(&f.keepSkipped, "keep-skipped", false, "include skipped tests in the report")
+	f.StringVar(&f.configFile, "config", os.Getenv(testrunner.ConfigFileEnv),
+		"path of a runner-wide config file, defaults to $"+testrunner.ConfigFileEnv)
+	f.Func("set", "set a config value in the form key=value, can be repeated", func(value string) error {
+		if !strings.Contains(value, "=") {
+			return fmt.Errorf("%q is not in the form key=value", value)
+		}
+		f.settings = append(f.settings, value)
+		return nil
+	})
+	f.IntVar(&f.limits.MaxLineBytes, "max-line-bytes", f.limits.MaxLineBytes,
+		"maximum length of a single line of test output, longer lines are truncated")
+	f.IntVar(&f.limits.MaxTestOutputBytes, "max-test-output-bytes", f.limits.MaxTestOutputBytes,
+		"maximum amount of output collected per test")
+	f.Int64Var(&f.limits.MaxOutputBytes, "max-output-bytes", f.limits.MaxOutputBytes,
+		"maximum amount of test output in total, the tests are stopped when it is exceeded")
+	f.Usage = func() {
+		fmt.Fprintln(f.Output(), usage)
+		f.PrintDefaults()
+	}
+	return f
+}
+
+func (f *runnerFlags) parse(args []string) {
+	if err := f.Parse(args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// options returns the options for the test runner. Only the limits that were set
+// explicitly take precedence over the exercise config, the others are filled in by
+// the test runner.
+func (f *runnerFlags) options() testrunner.Options {
+	var limits testrunner.OutputLimits
+	var settings []string
+	f.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "max-line-bytes":
+			limits.MaxLineBytes = f.limits.MaxLineBytes
+		case "max-test-output-bytes":
+			limits.MaxTestOutputBytes = f.limits.MaxTestOutputBytes
+		case "max-output-bytes":
+			limits.MaxOutputBytes = f.limits.MaxOutputBytes
+		case "keep-skipped":
+			settings = append(settings, fmt.Sprintf("keepSkippedTests=%t", f.keepSkipped))
+		}
+	})
+
+	return testrunner.Options{
+		OutputLimits: limits,
+		Config: testrunner.ConfigSources{
+			File:  f.configFile,
+			Env:   os.Environ(),
+			Flags: append(settings, f.settings...),
+		},
+	}
+}
+
 func checkArgs(input_dir string, output_dir string) (string, bool) {
 	if _, err := os.Stat(input_dir); os.IsNotExist(err) {
 		return fmt.Sprintf("input_dir does not exist: %s", input_dir), false
@@ -33,6 +33,26 @@ func TestMain_fail(t *testing.T) {
 			name: "missing input_dir",
 			args: []string{"progpath", "bad_input_dir", "noop"},
 		},
+		{
+			name: "unknown flag",
+			args: []string{"progpath", "-unknown", "testrunner", "noop"},
+		},
+		{
+			name: "print-config without input_dir",
+			args: []string{"progpath", "print-config"},
+		},
+		{
+			name: "flaky-check without input_dir",
+			args: []string{"progpath", "flaky-check", "-runs", "3"},
+		},
+		{
+			name: "mutate without input_dir",
+			args: []string{"progpath", "mutate"},
+		},
+		{
+			name: "invalid setting",
+			args: []string{"progpath", "-set", "timeout", "testrunner", "noop"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,97 @@
+package testrunner
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"log"
+	"path/filepath"
+	"slices"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/analysis/passes/assign"
+	"golang.org/x/tools/go/analysis/passes/bools"
+	"golang.org/x/tools/go/analysis/passes/loopclosure"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/analysis/passes/unreachable"
+	"golang.org/x/tools/go/analysis/passes/unusedresult"
+	"golang.org/x/tools/go/packages"
+)
+
+// vetAnalyzers are the analyzers that run on the solution if vet is enabled in the
+// exercise config. They are selected for finding mistakes that are common in solutions
+// but are not reported by the subset of vet checks that `go test` runs.
+var vetAnalyzers = []*analysis.Analyzer{
+	assign.Analyzer,
+	bools.Analyzer,
+	loopclosure.Analyzer,
+	printf.Analyzer,
+	shadow.Analyzer,
+	unreachable.Analyzer,
+	unusedresult.Analyzer,
+}
+
+// runAnalyzers runs the vet analyzers on the non-test files of the tested packages and
+// returns the findings as warnings. Problems with running the analyzers are only logged,
+// the analyzers never change the outcome of the test run.
+func runAnalyzers(input_dir string, cfg ExerciseConfig) []testWarning {
+	solutionDir, err := filepath.Abs(input_dir)
+	if err != nil {
+		log.Printf("warning: analyzers were not run, failed to resolve %s: %s", input_dir, err)
+		return nil
+	}
+
+	loadCfg := &packages.Config{
+		Mode:       packages.LoadAllSyntax,
+		Dir:        solutionDir,
+		Env:        goEnv(cfg),
+		BuildFlags: buildFlags(cfg),
+	}
+	pkgs, err := packages.Load(loadCfg, testPattern(cfg))
+	if err != nil {
+		log.Printf("warning: analyzers were not run, failed to load the solution: %s", err)
+		return nil
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		log.Printf("warning: analyzers were not run, the solution has errors")
+		return nil
+	}
+
+	graph, err := checker.Analyze(vetAnalyzers, pkgs, nil)
+	if err != nil {
+		log.Printf("warning: failed to run the analyzers: %s", err)
+		return nil
+	}
+
+	type finding struct {
+		position token.Position
+		message  string
+	}
+	var findings []finding
+	for _, act := range graph.Roots {
+		for _, diagnostic := range act.Diagnostics {
+			position := act.Package.Fset.Position(diagnostic.Pos)
+			file, err := filepath.Rel(solutionDir, position.Filename)
+			if err != nil || !filepath.IsLocal(file) {
+				continue
+			}
+			position.Filename = file
+			findings = append(findings, finding{position, fmt.Sprintf("%s (%s)", diagnostic.Message, act.Analyzer.Name)})
+		}
+	}
+	slices.SortFunc(findings, func(a, b finding) int {
+		return cmp.Or(
+			cmp.Compare(a.position.Filename, b.position.Filename),
+			cmp.Compare(a.position.Offset, b.position.Offset),
+			cmp.Compare(a.message, b.message),
+		)
+	})
+
+	var warnings []testWarning
+	for _, f := range slices.Compact(findings) {
+		warnings = append(warnings, testWarning{Kind: warnVet, Message: fmt.Sprintf("%s: %s", f.position, f.message)})
+	}
+	return warnings
+}
@@ -2,9 +2,11 @@ package testrunner
 
 import (
 	"bytes"
+	"cmp"
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/format"
 	"go/importer"
 	"go/parser"
@@ -14,6 +16,7 @@ import (
 	"log"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -34,11 +37,12 @@ type subTestAstInfo struct {
 }
 
 type rootLevelTest struct {
-	name     string
-	fileName string
-	code     string
-	taskID   uint64
-	pkgName  string
+	name      string
+	fileName  string
+	code      string
+	taskID    uint64
+	taskIDErr error // set if the task ID annotation is malformed
+	pkgName   string
 }
 
 // FindAllRootLevelTests parses the test file and extracts the name,
@@ -55,8 +59,12 @@ func FindAllRootLevelTests(fileNames []string) []rootLevelTest {
 			return nil
 		}
 		for _, d := range file.Decls {
-			if f, ok := d.(*ast.FuncDecl); ok && strings.HasPrefix(f.Name.Name, "Test") {
-				taskID := findTaskID(f.Doc)
+			// TestMain is not a test but sets up the test binary.
+			if f, ok := d.(*ast.FuncDecl); ok && strings.HasPrefix(f.Name.Name, "Test") && f.Name.Name != "TestMain" {
+				taskID, taskIDErr := findTaskID(f.Doc)
+				if taskIDErr != nil {
+					log.Printf("warning: invalid task ID for %s: %s", f.Name.Name, taskIDErr)
+				}
 				fun := &printer.CommentedNode{Node: f, Comments: file.Comments}
 				var buf bytes.Buffer
 				printErr := printer.Fprint(&buf, fset, fun)
@@ -67,11 +75,12 @@ func FindAllRootLevelTests(fileNames []string) []rootLevelTest {
 				}
 
 				tests = append(tests, rootLevelTest{
-					name:     f.Name.Name,
-					fileName: fileName,
-					code:     buf.String(),
-					taskID:   taskID,
-					pkgName:  file.Name.Name,
+					name:      f.Name.Name,
+					fileName:  fileName,
+					code:      buf.String(),
+					taskID:    taskID,
+					taskIDErr: taskIDErr,
+					pkgName:   file.Name.Name,
 				})
 			}
 		}
@@ -91,24 +100,35 @@ var taskIDFormat = regexp.MustCompile(`testRunnerTaskID=([0-9]+)`)
 
 // findTaskID checks whether there is a task ID set in a function comment,
 // e.g. "testRunnerTaskID=2".
-// If no task ID was identified, 0 is returned.
-func findTaskID(doc *ast.CommentGroup) uint64 {
-	matches := taskIDFormat.FindStringSubmatch(doc.Text())
-	if len(matches) != 2 {
-		return 0
+// If no task ID was identified, 0 is returned. An error is returned if the
+// annotation is repeated, cannot be parsed or is explicitly set to 0.
+func findTaskID(doc *ast.CommentGroup) (uint64, error) {
+	text := doc.Text()
+	matches := taskIDFormat.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		if strings.Contains(text, "testRunnerTaskID") {
+			return 0, errors.New("testRunnerTaskID annotation must have the format testRunnerTaskID=<number>")
+		}
+		return 0, nil
+	}
+	if len(matches) > 1 {
+		return 0, fmt.Errorf("found %d testRunnerTaskID annotations, expected only one", len(matches))
 	}
 
-	taskID, err := strconv.ParseUint(matches[1], 10, 64)
+	taskID, err := strconv.ParseUint(matches[0][1], 10, 64)
 	if err != nil {
-		log.Println("warning: failed to parse testRunnerTaskID value")
-		return 0
+		return 0, fmt.Errorf("failed to parse testRunnerTaskID value: %w", err)
+	}
+	if taskID == 0 {
+		return 0, errors.New("testRunnerTaskID must be greater than 0")
 	}
 
-	return taskID
+	return taskID, nil
 }
 
-// generate simplified test code corresponding to a subtest
-func getSubCode(test string, sub string, code string, file string, pkgName string) string {
+// generate simplified test code corresponding to a subtest,
+// together with the task ID that was set on the test data entry of the subtest (if any)
+func getSubCode(test string, sub string, code string, file string, pkgName string) (string, uint64, error) {
 	pkgLine := fmt.Sprintf("package %s\n", pkgName)
 	fset := token.NewFileSet()
 	f, err := parser.ParseFile(
@@ -116,15 +136,15 @@ func getSubCode(test string, sub string, code string, file string, pkgName strin
 	)
 	if err != nil {
 		log.Printf("warning: '%s' not parsed from '%s': %s", test, file, err)
-		return ""
+		return "", 0, nil
 	}
 
-	typeInfo := resolveTestData(fset, f, file)
+	typeInfo, files := resolveTestData(fset, f, file)
 
 	fAST, ok := f.Decls[0].(*ast.FuncDecl)
 	if !ok {
 		log.Println("warning: first subtest declaration must be a function")
-		return ""
+		return "", 0, nil
 	}
 
 	fbAST := fAST.Body.List // f.Decls[0].Body.List
@@ -132,23 +152,58 @@ func getSubCode(test string, sub string, code string, file string, pkgName strin
 	astInfo, err := findTestDataAndRange(fbAST, fset, typeInfo)
 	if err != nil {
 		log.Printf("warning: could not find test table and/or range: %v\n", err)
-		return ""
+		return "", 0, nil
 	}
 
 	// process the test data assignment
 	metadata, ok := processTestDataAssgn(sub, astInfo.testDataAst, typeInfo)
 	if !ok {
-		return ""
+		return "", 0, nil
 	}
 	lhs1 := astInfo.testDataAst.Lhs[0].(*ast.Ident)        // f.Decls[0].Body.List[0].Lhs[0]
 	rhs1 := astInfo.testDataAst.Rhs[0].(*ast.CompositeLit) // f.Decls[0].Body.List[0].Rhs[0]
 
+	taskID, taskIDErr := findEntryTaskID(fset, files, typeInfo, metadata.TD, getAllFieldNames(rhs1.Type))
+	if taskIDErr != nil {
+		log.Printf("warning: invalid task ID for %s/%s: %s", test, sub, taskIDErr)
+	}
+
 	// process the range statement
 	ok = processRange(metadata, astInfo.rangeAst)
 	if !ok {
-		return ""
+		return "", taskID, taskIDErr
 	}
 
+	// comments of the other test data entries would end up at random places
+	// in the extracted code, so they are removed. The comments directly before
+	// the entry, e.g. its task ID, are moved above the test data.
+	prevEnd := rhs1.Lbrace
+	for _, elt := range rhs1.Elts {
+		if elt == ast.Expr(metadata.TD) {
+			break
+		}
+		prevEnd = elt.End()
+	}
+	leading := func(c *ast.CommentGroup) bool {
+		return c.Pos() > prevEnd && c.End() < metadata.TD.Lbrace &&
+			fset.Position(c.Pos()).Line > fset.Position(prevEnd).Line
+	}
+	f.Comments = slices.DeleteFunc(f.Comments, func(c *ast.CommentGroup) bool {
+		inTable := c.Pos() > rhs1.Lbrace && c.End() < rhs1.Rbrace
+		inEntry := c.Pos() > metadata.TD.Lbrace && c.End() < metadata.TD.Rbrace
+		return inTable && !inEntry && !leading(c)
+	})
+	for _, c := range f.Comments {
+		if leading(c) {
+			for _, comment := range c.List {
+				comment.Slash = astInfo.testDataAst.Pos() - 1
+			}
+		}
+	}
+	slices.SortStableFunc(f.Comments, func(a, b *ast.CommentGroup) int {
+		return cmp.Compare(a.Pos(), b.Pos())
+	})
+
 	// rename the test data to match the variable assigned in the range stmt
 	lhs1.Name = metadata.newTDName
 	// assign the subtest data to the new test data variable
@@ -160,12 +215,12 @@ func getSubCode(test string, sub string, code string, file string, pkgName strin
 	var buf bytes.Buffer
 	if err := format.Node(&buf, fset, f); err != nil {
 		log.Println("warning: failed to format extracted AST for subtest")
-		return ""
+		return "", taskID, taskIDErr
 	}
 	if astInfo.testDataAstIdx != -1 { // testDataAst is already in the test function
-		return strings.TrimSpace(strings.TrimPrefix(buf.String(), pkgLine))
+		return strings.TrimSpace(strings.TrimPrefix(buf.String(), pkgLine)), taskID, taskIDErr
 	}
-	return insertTestDataASTIntoFunc(fset, astInfo.testDataAst, fAST.Body, buf.Bytes(), pkgLine)
+	return insertTestDataASTIntoFunc(fset, astInfo.testDataAst, fAST.Body, buf.Bytes(), pkgLine), taskID, taskIDErr
 }
 
 func findTestDataAndRange(stmtList []ast.Stmt, fset *token.FileSet, info *types.Info) (subTestAstInfo, error) {
@@ -309,6 +364,70 @@ func processTestDataAssgn(sub string, assgn *ast.AssignStmt, info *types.Info) (
 	return nil, false
 }
 
+// taskIDField is the name of the test data field that can hold the task ID of a subtest.
+const taskIDField = "taskID"
+
+// findEntryTaskID checks whether a task ID was set for a single test data entry,
+// either via the "taskID" field or via a "testRunnerTaskID=2" comment on or inside the entry.
+// If no task ID was identified, 0 is returned.
+func findEntryTaskID(fset *token.FileSet, files []*ast.File, info *types.Info, entry *ast.CompositeLit, fieldNames []string) (uint64, error) {
+	for i, elt := range entry.Elts {
+		var key string
+		value := elt
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			ident, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			key, value = ident.Name, kv.Value
+		} else if i < len(fieldNames) {
+			key = fieldNames[i]
+		}
+		if key != taskIDField {
+			continue
+		}
+		taskID, ok := integerConstant(info, value)
+		if !ok {
+			return 0, fmt.Errorf("%s field must be an integer constant, found %s", taskIDField, types.ExprString(value))
+		}
+		if taskID == 0 {
+			return 0, fmt.Errorf("%s must be greater than 0", taskIDField)
+		}
+		return taskID, nil
+	}
+
+	for _, file := range files {
+		if entry.Pos() < file.FileStart || entry.Pos() > file.FileEnd {
+			continue
+		}
+		// The comment map associates comments directly before or after the entry
+		// with the entry itself, comments inside with the respective child node.
+		var comments ast.CommentGroup
+		for _, group := range ast.NewCommentMap(fset, file, file.Comments).Filter(entry).Comments() {
+			comments.List = append(comments.List, group.List...)
+		}
+		return findTaskID(&comments)
+	}
+	return 0, nil
+}
+
+// integerConstant returns the value of an expression that evaluates to a non-negative
+// integer constant, e.g. a literal or a named constant of the test file.
+func integerConstant(info *types.Info, expr ast.Expr) (uint64, bool) {
+	if info != nil {
+		if tv, ok := info.Types[expr]; ok && tv.Value != nil {
+			return constant.Uint64Val(constant.ToInt(tv.Value))
+		}
+	}
+	// Without type information, only literals are understood.
+	lit, ok := ast.Unparen(expr).(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return 0, false
+	}
+	value, err := strconv.ParseUint(lit.Value, 0, 64)
+	return value, err == nil
+}
+
 // getAllFieldNames returns all the field names of anonymous struct type
 // not support for named struct type yet
 func getAllFieldNames(exp ast.Expr) []string {
@@ -374,12 +493,12 @@ func processRange(metadata *subTData, rastmt *ast.RangeStmt) bool {
 }
 
 // resolveTestData resolves test data variable declared in cases_test.go (if exists)
-// and returns type information for identifier resolution
-func resolveTestData(fset *token.FileSet, f *ast.File, file string) *types.Info {
+// and returns type information for identifier resolution together with all parsed files
+func resolveTestData(fset *token.FileSet, f *ast.File, file string) (*types.Info, []*ast.File) {
 	glob := filepath.Join(filepath.Dir(file), "*_test.go")
 	filepaths, err := filepath.Glob(glob)
 	if err != nil {
-		return nil
+		return nil, nil
 	}
 
 	files := []*ast.File{f}
@@ -387,11 +506,11 @@ func resolveTestData(fset *token.FileSet, f *ast.File, file string) *types.Info
 		fdata, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
 		if err != nil {
 			log.Printf("parser.ParseFile(%q) failed: %v", file, err)
-			return nil
+			return nil, nil
 		}
 		if fdata == nil {
 			log.Printf("parser.ParseFile(%q) returned nil", file)
-			return nil
+			return nil, nil
 		}
 		files = append(files, fdata)
 	}
@@ -404,14 +523,15 @@ func resolveTestData(fset *token.FileSet, f *ast.File, file string) *types.Info
 
 	// Type check the package
 	info := &types.Info{
-		Defs: make(map[*ast.Ident]types.Object),
-		Uses: make(map[*ast.Ident]types.Object),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+		Types: make(map[ast.Expr]types.TypeAndValue),
 	}
 
 	// Type check - ignore errors since files may have missing imports
 	_, _ = conf.Check("", fset, files, info)
 
-	return info
+	return info, files
 }
 
 // insertTestDataASTIntoFunc inserts testDataAst into the first line of fbAST function's body
@@ -1,6 +1,10 @@
 package testrunner
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"path/filepath"
 	"testing"
 )
@@ -36,3 +40,113 @@ func TestGetFuncCode(t *testing.T) {
 		})
 	}
 }
+
+func TestFindTaskID(t *testing.T) {
+	tests := []struct {
+		name    string
+		comment string
+		taskID  uint64
+		wantErr bool
+	}{
+		{
+			name:    "no task id",
+			comment: "// Some comment",
+		},
+		{
+			name:    "task id with other text",
+			comment: "// testRunnerTaskID=3 more text",
+			taskID:  3,
+		},
+		{
+			name:    "task id set to zero",
+			comment: "// testRunnerTaskID=0",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate task id",
+			comment: "// testRunnerTaskID=1\n// testRunnerTaskID=2",
+			wantErr: true,
+		},
+		{
+			name:    "malformed task id",
+			comment: "// testRunnerTaskID: 1",
+			wantErr: true,
+		},
+		{
+			name:    "task id overflow",
+			comment: "// testRunnerTaskID=99999999999999999999",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\n\n" + tt.comment + "\nfunc TestX(t *testing.T) {}\n"
+			f, err := parser.ParseFile(token.NewFileSet(), "x_test.go", src, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("failed to parse test source: %s", err)
+			}
+			taskID, err := findTaskID(f.Decls[0].(*ast.FuncDecl).Doc)
+			if taskID != tt.taskID || (err != nil) != tt.wantErr {
+				t.Errorf("findTaskID(%q) = %d, %v; want %d, error: %t",
+					tt.comment, taskID, err, tt.taskID, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindEntryTaskID(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		taskID  uint64
+		wantErr bool
+	}{
+		{
+			name:   "integer literal",
+			value:  "2",
+			taskID: 2,
+		},
+		{
+			name:   "named constant",
+			value:  "taskTwo",
+			taskID: 2,
+		},
+		{
+			name:   "constant expression",
+			value:  "taskTwo + 1",
+			taskID: 3,
+		},
+		{
+			name:    "zero",
+			value:   "taskTwo - 2",
+			wantErr: true,
+		},
+		{
+			name:    "not a constant",
+			value:   "next()",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package p\n\nconst taskTwo = 2\n\nfunc next() int { return 1 }\n\n" +
+				"var tests = []struct {\n\tname   string\n\ttaskID int\n}{\n\t{name: \"x\", taskID: " + tt.value + "},\n}\n"
+			fset := token.NewFileSet()
+			f, err := parser.ParseFile(fset, "x_test.go", src, parser.ParseComments)
+			if err != nil {
+				t.Fatalf("failed to parse test source: %s", err)
+			}
+			info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+			if _, err := (&types.Config{}).Check("p", fset, []*ast.File{f}, info); err != nil {
+				t.Fatalf("failed to type check test source: %s", err)
+			}
+			table := f.Decls[2].(*ast.GenDecl).Specs[0].(*ast.ValueSpec).Values[0].(*ast.CompositeLit)
+			entry := table.Elts[0].(*ast.CompositeLit)
+			taskID, err := findEntryTaskID(fset, []*ast.File{f}, info, entry, getAllFieldNames(table.Type))
+			if taskID != tt.taskID || (err != nil) != tt.wantErr {
+				t.Errorf("findEntryTaskID(%q) = %d, %v; want %d, error: %t",
+					tt.value, taskID, err, tt.taskID, tt.wantErr)
+			}
+		})
+	}
+}
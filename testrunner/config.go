@@ -0,0 +1,339 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// ExerciseConfig contains the settings for testing a solution. The values are
+// combined from several sources, see loadExerciseConfig.
+type ExerciseConfig struct {
+	TestingFlags   []string `json:"testingFlags"`
+	TaskIDsEnabled bool     `json:"taskIdsEnabled"`
+	// StrictTaskIDs turns task ID misconfigurations into a report level error
+	// instead of warnings.
+	StrictTaskIDs bool `json:"strictTaskIds"`
+	// Tasks is the number of tasks of the exercise, used to validate the task IDs.
+	Tasks int `json:"tasks"`
+	// KeepSkippedTests includes skipped tests in the report instead of only counting them.
+	KeepSkippedTests bool `json:"keepSkippedTests"`
+	// IncludePanicTrace appends the full goroutine trace to the summary of a panic.
+	IncludePanicTrace bool `json:"includePanicTrace"`
+	// BuildTags are passed to every invocation of the go command via -tags.
+	BuildTags []string `json:"buildTags"`
+	// GoExperiment contains the values for the GOEXPERIMENT environment variable.
+	GoExperiment []string `json:"goExperiment"`
+	// Timeout is the maximum duration of the test run, e.g. "30s".
+	Timeout string `json:"timeout"`
+	// MaxOutputBytes overrides the default for the maximum amount of output of the tests.
+	MaxOutputBytes int64 `json:"maxOutputBytes"`
+	// MaxMemoryBytes limits the memory of the test binary (Linux only).
+	MaxMemoryBytes int64 `json:"maxMemoryBytes"`
+	// AllPackages runs the tests of all packages of the solution (./...) instead of
+	// only the package in the solution directory.
+	AllPackages bool `json:"allPackages"`
+	// VerifyModules checks that the modules required by the solution are available
+	// without network access before running the tests.
+	VerifyModules bool `json:"verifyModules"`
+	// Vet runs additional vet analyzers on the solution, their findings are added as warnings.
+	Vet bool `json:"vet"`
+
+	// warnings collects problems found while reading the config, they are added to the report.
+	warnings []testWarning
+	// unknownKeys describes the unknown config keys of the runner-wide sources. They do not
+	// concern the exercise, so they are logged and shown by print-config, but not added to the report.
+	// Unknown keys in .meta/config.json are reported in the warnings like other config problems.
+	unknownKeys []string
+	// overlayFile is passed to the go command with -overlay if files of the solution are replaced.
+	overlayFile string
+	// localToolchain prevents the go command from switching to the toolchain required by go.mod.
+	localToolchain bool
+}
+
+// defaultExerciseConfig contains the built-in default values.
+var defaultExerciseConfig = ExerciseConfig{
+	VerifyModules: true,
+}
+
+// ConfigSources describes where config values come from besides the built-in
+// defaults and the custom section of the .meta/config.json file of the exercise.
+type ConfigSources struct {
+	// File is the path of a runner-wide config file with a json object of config values.
+	File string
+	// Env contains environment variables in the form NAME=value, e.g. from os.Environ.
+	// Variables starting with ConfigEnvPrefix set config values.
+	Env []string
+	// Flags contains config values from the command line in the form key=value.
+	Flags []string
+}
+
+const (
+	// ConfigEnvPrefix is the prefix of the environment variables that set config values,
+	// e.g. GO_TEST_RUNNER_KEEP_SKIPPED_TESTS=true sets keepSkippedTests.
+	ConfigEnvPrefix = "GO_TEST_RUNNER_"
+	// ConfigFileEnv is the environment variable with the path of the runner-wide config file.
+	ConfigFileEnv = ConfigEnvPrefix + "CONFIG"
+)
+
+const (
+	sourceDefault  = "default"
+	sourceEnv      = "environment"
+	sourceExercise = ".meta/config.json"
+	sourceFlags    = "command line"
+)
+
+// configLayer contains the config values of one source, keyed by their json names.
+type configLayer struct {
+	source string
+	values map[string]json.RawMessage
+}
+
+// configKeys maps the json names of the fields of ExerciseConfig to their types.
+var configKeys = func() map[string]reflect.Type {
+	keys := map[string]reflect.Type{}
+	t := reflect.TypeFor[ExerciseConfig]()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); field.IsExported() && name != "" {
+			keys[name] = field.Type
+		}
+	}
+	return keys
+}()
+
+// loadExerciseConfig combines the config values of all sources. Later sources take
+// precedence over earlier ones: built-in defaults, the runner-wide config file,
+// environment variables, the custom section of .meta/config.json and the command line.
+// Unknown keys and invalid values are ignored. Invalid values and unknown keys in
+// .meta/config.json are reported as warnings, the other unknown keys are only logged.
+// It also returns the source of the effective value of every key.
+func loadExerciseConfig(input_dir string, sources ConfigSources) (ExerciseConfig, map[string]string) {
+	var warnings, unknownKeys []string
+	layers := []configLayer{structLayer(sourceDefault, defaultExerciseConfig)}
+	if sources.File != "" {
+		layer, err := fileLayer(sources.File)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+		}
+		layers = append(layers, layer)
+	}
+	envLayer, envUnknownKeys := textLayer(sourceEnv, envValues(sources.Env))
+	unknownKeys = append(unknownKeys, envUnknownKeys...)
+	layers = append(layers, envLayer)
+	layer, err := exerciseLayer(input_dir)
+	if err != nil {
+		warnings = append(warnings, err.Error())
+	}
+	layers = append(layers, layer)
+	flagsLayer, flagsUnknownKeys := textLayer(sourceFlags, flagValues(sources.Flags))
+	unknownKeys = append(unknownKeys, flagsUnknownKeys...)
+	layers = append(layers, flagsLayer)
+
+	merged := map[string]json.RawMessage{}
+	keySources := map[string]string{}
+	for _, layer := range layers {
+		for _, key := range slices.Sorted(maps.Keys(layer.values)) {
+			value := layer.values[key]
+			t, ok := configKeys[key]
+			if !ok {
+				unknownKey := fmt.Sprintf("unknown config key %q in %s", key, layer.source)
+				if layer.source == sourceExercise {
+					// Most likely a typo of the exercise maintainers, e.g. "strictTaskIDs".
+					warnings = append(warnings, unknownKey)
+				} else {
+					unknownKeys = append(unknownKeys, unknownKey)
+				}
+				continue
+			}
+			if err := json.Unmarshal(value, reflect.New(t).Interface()); err != nil {
+				warnings = append(warnings, fmt.Sprintf("invalid value for config key %q in %s: %s", key, layer.source, err))
+				continue
+			}
+			merged[key] = value
+			keySources[key] = layer.source
+		}
+	}
+
+	cfg := ExerciseConfig{}
+	content, err := json.Marshal(merged)
+	if err == nil {
+		err = json.Unmarshal(content, &cfg)
+	}
+	if err != nil {
+		log.Fatalf("failed to combine the config values: %s", err)
+	}
+	for _, warning := range warnings {
+		cfg.addConfigWarning(warning)
+	}
+	for _, unknownKey := range unknownKeys {
+		log.Printf("warning: %s", unknownKey)
+	}
+	cfg.unknownKeys = unknownKeys
+
+	if len(cfg.TestingFlags) != 0 {
+		flags, flagWarnings := validateTestingFlags(cfg.TestingFlags)
+		cfg.TestingFlags = flags
+		cfg.warnings = append(cfg.warnings, flagWarnings...)
+	}
+	validateBuildConfig(&cfg)
+	validateLimits(&cfg)
+
+	return cfg, keySources
+}
+
+func (cfg *ExerciseConfig) addConfigWarning(message string) {
+	log.Printf("warning: %s", message)
+	cfg.warnings = append(cfg.warnings, testWarning{Kind: warnConfig, Message: message})
+}
+
+func structLayer(source string, cfg ExerciseConfig) configLayer {
+	layer := configLayer{source: source}
+	content, err := json.Marshal(cfg)
+	if err == nil {
+		err = json.Unmarshal(content, &layer.values)
+	}
+	if err != nil {
+		log.Fatalf("failed to convert the %s config values: %s", source, err)
+	}
+	return layer
+}
+
+func fileLayer(path string) (configLayer, error) {
+	layer := configLayer{source: path}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return layer, fmt.Errorf("runner config could not be read: %w", err)
+	}
+	if err := json.Unmarshal(content, &layer.values); err != nil {
+		return layer, fmt.Errorf("failed to parse runner config %s: %w", path, err)
+	}
+	return layer, nil
+}
+
+// exerciseLayer reads the custom section of the .meta/config.json file of the exercise.
+// A missing file is not an error, most exercises do not need any config.
+func exerciseLayer(input_dir string) (configLayer, error) {
+	layer := configLayer{source: sourceExercise}
+	configContent, err := os.ReadFile(filepath.Join(input_dir, ".meta", "config.json"))
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("warning: config.json could not be read: %v", err)
+		return layer, nil
+	}
+	if err != nil {
+		return layer, fmt.Errorf("config.json could not be read: %w", err)
+	}
+
+	cfg := struct {
+		Custom map[string]json.RawMessage `json:"custom"`
+	}{}
+	if err := json.Unmarshal(configContent, &cfg); err != nil {
+		return layer, fmt.Errorf("failed to parse config.json: %w", err)
+	}
+	layer.values = cfg.Custom
+	return layer, nil
+}
+
+// textLayer converts config values given as text, e.g. from environment variables,
+// to json according to the type of the config key. Lists are separated by commas.
+// It also returns the descriptions of the unknown keys.
+func textLayer(source string, values map[string]string) (configLayer, []string) {
+	layer := configLayer{source: source, values: map[string]json.RawMessage{}}
+	var unknownKeys []string
+	for key, value := range values {
+		t, ok := configKeys[key]
+		if !ok {
+			unknownKeys = append(unknownKeys, fmt.Sprintf("unknown config key %q in %s", key, source))
+			continue
+		}
+		var raw json.RawMessage
+		switch t.Kind() {
+		case reflect.String:
+			raw, _ = json.Marshal(value)
+		case reflect.Slice:
+			raw, _ = json.Marshal(strings.Split(value, ","))
+		default:
+			raw = json.RawMessage(value)
+		}
+		layer.values[key] = raw
+	}
+	slices.Sort(unknownKeys)
+	return layer, unknownKeys
+}
+
+// envValues returns the config values set via environment variables, keyed by config key.
+// Variables with unknown names are kept with their name, so they are reported as unknown keys.
+func envValues(env []string) map[string]string {
+	keysByEnvName := map[string]string{}
+	for key := range configKeys {
+		keysByEnvName[envName(key)] = key
+	}
+
+	values := map[string]string{}
+	for _, variable := range env {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, ConfigEnvPrefix) || name == ConfigFileEnv {
+			continue
+		}
+		if key, ok := keysByEnvName[name]; ok {
+			values[key] = value
+		} else {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// envName returns the name of the environment variable for a config key,
+// e.g. GO_TEST_RUNNER_KEEP_SKIPPED_TESTS for keepSkippedTests.
+func envName(key string) string {
+	var name strings.Builder
+	name.WriteString(ConfigEnvPrefix)
+	for i, r := range key {
+		if unicode.IsUpper(r) && i > 0 {
+			name.WriteRune('_')
+		}
+		name.WriteRune(unicode.ToUpper(r))
+	}
+	return name.String()
+}
+
+func flagValues(flags []string) map[string]string {
+	values := map[string]string{}
+	for _, flag := range flags {
+		key, value, _ := strings.Cut(flag, "=")
+		values[key] = value
+	}
+	return values
+}
+
+// effectiveConfig is the output of PrintConfig.
+type effectiveConfig struct {
+	Config   ExerciseConfig    `json:"config"`
+	Sources  map[string]string `json:"sources"`
+	Warnings []testWarning     `json:"warnings,omitempty"`
+}
+
+// PrintConfig returns the effective config for the solution in input_dir as json,
+// together with the source of every value and the warnings found while reading the config,
+// including the unknown keys.
+func PrintConfig(input_dir string, sources ConfigSources) []byte {
+	cfg, keySources := loadExerciseConfig(input_dir, sources)
+	warnings := cfg.warnings
+	for _, unknownKey := range cfg.unknownKeys {
+		warnings = append(warnings, testWarning{Kind: warnConfig, Message: unknownKey})
+	}
+	bts, err := json.MarshalIndent(effectiveConfig{Config: cfg, Sources: keySources, Warnings: warnings}, "", "\t")
+	if err != nil {
+		log.Fatalf("Failed to marshal the config: %s", err)
+	}
+	return bts
+}
@@ -0,0 +1,94 @@
+package testrunner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadExerciseConfig(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, ".meta", "config.json"), `{
+		"blurb": "not part of the custom section",
+		"custom": {
+			"taskIdsEnabled": true,
+			"timeout": "20s",
+			"tasks": "three",
+			"unknownKey": 1
+		}
+	}`)
+	runnerConfig := filepath.Join(t.TempDir(), "runner.json")
+	writeFile(t, runnerConfig, `{"timeout": "10s", "includePanicTrace": true, "maxOutputBytes": 2048}`)
+
+	cfg, sources := loadExerciseConfig(input_dir, ConfigSources{
+		File: runnerConfig,
+		Env: []string{
+			"HOME=/home/user",
+			"GO_TEST_RUNNER_CONFIG=ignored.json",
+			"GO_TEST_RUNNER_MAX_OUTPUT_BYTES=4096",
+			"GO_TEST_RUNNER_BUILD_TAGS=exercism,integration",
+			"GO_TEST_RUNNER_UNKNOWN=1",
+		},
+		Flags: []string{"keepSkippedTests=true", "maxOutputBytes=8192"},
+	})
+
+	var warnings []string
+	for _, warning := range cfg.warnings {
+		assert.Equal(t, warnConfig, warning.Kind)
+		warnings = append(warnings, warning.Message)
+	}
+	unknownKeys := cfg.unknownKeys
+	cfg.warnings, cfg.unknownKeys = nil, nil
+
+	assert.Equal(t, ExerciseConfig{
+		TaskIDsEnabled:    true,
+		KeepSkippedTests:  true,
+		IncludePanicTrace: true,
+		BuildTags:         []string{"exercism", "integration"},
+		Timeout:           "20s",
+		MaxOutputBytes:    8192,
+		VerifyModules:     true,
+	}, cfg)
+	assert.Equal(t, sourceExercise, sources["taskIdsEnabled"])
+	assert.Equal(t, sourceExercise, sources["timeout"])
+	assert.Equal(t, runnerConfig, sources["includePanicTrace"])
+	assert.Equal(t, sourceEnv, sources["buildTags"])
+	assert.Equal(t, sourceFlags, sources["maxOutputBytes"])
+	assert.Equal(t, sourceDefault, sources["tasks"])
+	assert.Equal(t, []string{
+		`invalid value for config key "tasks" in .meta/config.json: json: cannot unmarshal string into Go value of type int`,
+		`unknown config key "unknownKey" in .meta/config.json`,
+	}, warnings)
+	assert.Equal(t, []string{
+		`unknown config key "GO_TEST_RUNNER_UNKNOWN" in environment`,
+	}, unknownKeys)
+}
+
+func TestLoadExerciseConfig_InvalidFiles(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, ".meta", "config.json"), `{"custom": `)
+
+	cfg, _ := loadExerciseConfig(input_dir, ConfigSources{File: filepath.Join(input_dir, "missing.json")})
+
+	assert.Len(t, cfg.warnings, 2)
+	assert.Contains(t, cfg.warnings[0].Message, "runner config could not be read")
+	assert.Contains(t, cfg.warnings[1].Message, "failed to parse config.json")
+}
+
+func TestEnvName(t *testing.T) {
+	assert.Equal(t, "GO_TEST_RUNNER_KEEP_SKIPPED_TESTS", envName("keepSkippedTests"))
+	assert.Equal(t, "GO_TEST_RUNNER_TIMEOUT", envName("timeout"))
+	assert.Equal(t, "GO_TEST_RUNNER_STRICT_TASK_IDS", envName("strictTaskIds"))
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"math"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
@@ -21,49 +23,111 @@ const (
 	statErr  = "error"
 )
 
-// For security reasons, only testing flags that are included in the list below are processed.
-var allowedTestingFlags = []string{"-race"}
-
 type testResult struct {
-	Name     string `json:"name"`
-	Status   string `json:"status"`
-	TestCode string `json:"test_code"`
-	Message  string `json:"message"`
-	TaskID   uint64 `json:"task_id,omitempty"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	TestCode   string `json:"test_code"`
+	Message    string `json:"message"`
+	TaskID     uint64 `json:"task_id,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	// DataRaces contains the parsed reports of the race detector for the test.
+	DataRaces []dataRace `json:"data_races,omitempty"`
+
+	taskIDErr   error  // set if the task ID annotation for the test is malformed
+	packagePath string // import path of the package of the test, empty if only one package is tested
 }
 
+// reportVersion is the version of the test runner interface that the reports follow.
+const reportVersion = 3
+
 type testReport struct {
-	Status  string       `json:"status"`
-	Version int          `json:"version"`
-	Message string       `json:"message,omitempty"`
-	Tests   []testResult `json:"tests"`
+	Status   string        `json:"status"`
+	Version  int           `json:"version"`
+	Message  string        `json:"message,omitempty"`
+	Tests    []testResult  `json:"tests"`
+	Skipped  int           `json:"skipped,omitempty"`
+	Tasks    []taskSummary `json:"tasks,omitempty"`
+	Warnings []testWarning `json:"warnings,omitempty"`
+	// DataRaces contains the parsed reports of the race detector that
+	// could not be attributed to a test.
+	DataRaces []dataRace `json:"data_races,omitempty"`
+	// DurationMs is the total runtime of the test runner for the solution,
+	// including compiling the code and the tests.
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// testWarning describes a problem that was detected while creating the report
+// but that should not change the outcome of the test run.
+type testWarning struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
 }
 
+const (
+	warnTaskID = "task_id"
+	// warnTestingFlag is used for testing flags in the exercise config that were rejected.
+	warnTestingFlag = "testing_flag"
+	// warnConfig is used for invalid values in the exercise config.
+	warnConfig = "config"
+	// warnVet is used for the findings of the vet analyzers.
+	warnVet = "vet"
+	// warnGoMod is used for adjustments of the go.mod and go.sum files of the solution.
+	warnGoMod = "go_mod"
+)
+
 type testLine struct {
-	Time    time.Time
-	Action  string
-	Package string
-	Test    string
-	Elapsed float64
-	Output  string
+	Time        time.Time
+	Action      string
+	Package     string
+	Test        string
+	Elapsed     float64
+	Output      string
+	ImportPath  string // set for "build-output" and "build-fail" actions
+	FailedBuild string // set for package level "fail" actions if the build failed
 }
 
-func Execute(input_dir string) []byte {
+// Options contains settings provided when invoking the test runner.
+// They take precedence over the exercise configuration.
+type Options struct {
+	// OutputLimits restricts how much output of `go test` is processed.
+	OutputLimits OutputLimits
+	// Config describes the sources of the config values.
+	Config ConfigSources
+}
+
+func Execute(input_dir string, opts Options) []byte {
 	var report *testReport
-	ver := 3
+	start := time.Now()
 
-	exerciseConfig := parseExerciseConfig(input_dir)
-	cmdres, testsOk := runTests(input_dir, exerciseConfig.TestingFlags)
-	testOutput, err := parseTestOutput(cmdres)
-	if err != nil {
-		log.Fatalf("parsing test output: %s", err)
+	exerciseConfig, _ := loadExerciseConfig(input_dir, opts.Config)
+	limits := opts.OutputLimits
+	if limits.MaxOutputBytes == 0 {
+		limits.MaxOutputBytes = exerciseConfig.MaxOutputBytes
 	}
+	solutionOverlay := prepareOverlay(input_dir, &exerciseConfig)
+	defer solutionOverlay.remove()
 
-	if testsOk {
-		report = getStructureForTestsOk(testOutput, input_dir, ver, exerciseConfig.TaskIDsEnabled)
+	var moduleProblems []string
+	if exerciseConfig.VerifyModules {
+		moduleProblems = verifyModules(input_dir, exerciseConfig, solutionOverlay)
+	}
+
+	testsOk := false
+	if len(moduleProblems) > 0 {
+		// The tests cannot run without the modules, the go command would only report network errors.
+		report = &testReport{Status: statErr, Version: reportVersion, Message: strings.Join(moduleProblems, "\n")}
+	} else if testOutput, ok := runTests(input_dir, exerciseConfig, limits.withDefaults()); ok {
+		testsOk = true
+		report = getStructureForTestsOk(testOutput, input_dir, reportVersion, exerciseConfig)
 	} else {
-		report = getStructureForTestsNotOk(testOutput, ver)
+		report = getStructureForTestsNotOk(testOutput, input_dir, reportVersion, exerciseConfig)
+	}
+	report.Warnings = append(exerciseConfig.warnings, report.Warnings...)
+	if testsOk && exerciseConfig.Vet {
+		report.Warnings = append(report.Warnings, runAnalyzers(input_dir, exerciseConfig)...)
 	}
+	newPathNormalizer(input_dir).normalizeReport(report)
+	report.DurationMs = time.Since(start).Milliseconds()
 
 	bts, err := json.MarshalIndent(report, "", "\t")
 	if err != nil {
@@ -72,11 +136,11 @@ func Execute(input_dir string) []byte {
 	return bts
 }
 
-func getStructureForTestsNotOk(parsedOutput *parsedTestOutput, ver int) *testReport {
+func getStructureForTestsNotOk(parsedOutput *parsedTestOutput, input_dir string, ver int, cfg ExerciseConfig) *testReport {
 	report := &testReport{
 		Status:  statErr,
 		Version: ver,
-		Message: parsedOutput.joinPackageMessages("\n"),
+		Message: strings.Join(slices.Concat(parsedOutput.buildOutput, parsedOutput.pkgLevelMessages), "\n"),
 	}
 
 	report.Message += parsedOutput.joinFailMessages("\n")
@@ -93,10 +157,18 @@ func getStructureForTestsNotOk(parsedOutput *parsedTestOutput, ver int) *testRep
 		report.Message += "\n" + strings.Join(jsonOutputMessages, "\n")
 	}
 
+	hints := missingDeclarationHints(report.Message, input_dir)
+	if hasTestTypeErrors(report.Message) {
+		hints = append(hints, signatureHints(input_dir, cfg)...)
+	}
+	if len(hints) > 0 {
+		report.Message = strings.Join(hints, "\n") + "\n\n" + report.Message
+	}
+
 	return report
 }
 
-func getStructureForTestsOk(parsedOutput *parsedTestOutput, input_dir string, ver int, taskIDsEnabled bool) *testReport {
+func getStructureForTestsOk(parsedOutput *parsedTestOutput, input_dir string, ver int, cfg ExerciseConfig) *testReport {
 	report := &testReport{
 		Status:  statPass,
 		Version: ver,
@@ -108,7 +180,14 @@ func getStructureForTestsOk(parsedOutput *parsedTestOutput, input_dir string, ve
 		}
 	}()
 
-	tests := processTestResults(parsedOutput, input_dir, taskIDsEnabled)
+	var tests []testResult
+	for _, pkg := range solutionPackages(input_dir, cfg) {
+		pkgTests := packageTestResults(parsedOutput, pkg, cfg)
+		pkgTests = removeObsoleteParentTests(pkgTests)
+		pkgTests = summarizePanics(pkgTests, pkg.dir, cfg.IncludePanicTrace)
+		pkgTests = summarizeDataRaces(pkgTests, pkg.dir)
+		tests = append(tests, pkgTests...)
+	}
 
 	if parsedOutput.hasFailMessages() {
 		report.Status = statErr
@@ -118,18 +197,36 @@ func getStructureForTestsOk(parsedOutput *parsedTestOutput, input_dir string, ve
 
 	if len(tests) == 0 && parsedOutput.hasPackageMessages() {
 		report.Status = statErr
-		report.Message = parsedOutput.joinPackageMessages("")
+		solutionDir, _ := filepath.Abs(input_dir)
+		report.Message, report.DataRaces = summarizeDataRacesInMessage(parsedOutput.joinPackageMessages(""), solutionDir)
 		return report
 	}
 
-	tests = removeObsoleteParentTests(tests)
+	var taskIDProblems []string
+	if cfg.TaskIDsEnabled {
+		taskIDProblems = validateTaskIDs(tests, cfg.Tasks)
+		if !cfg.StrictTaskIDs {
+			for _, problem := range taskIDProblems {
+				report.Warnings = append(report.Warnings, testWarning{Kind: warnTaskID, Message: problem})
+			}
+		}
+	}
+
+	// The task IDs are assigned by parent test, before the names are prefixed with the package path.
+	tests = cleanUpTaskIDs(tests, cfg.TaskIDsEnabled)
 	tests = formatTestNames(tests)
-	tests = cleanUpTaskIDs(tests, taskIDsEnabled)
+	tests = prefixPackagePaths(tests)
 
 	for _, test := range tests {
 		if test.Status == statSkip {
-			// There is no status for skipped tests on the website
-			// so we remove them from the output.
+			report.Skipped++
+			if !cfg.KeepSkippedTests {
+				// There is no status for skipped tests on the website
+				// so we remove them from the output by default.
+				continue
+			}
+			test.Message = skipReason(test.Message)
+			report.Tests = append(report.Tests, test)
 			continue
 		}
 		if test.Status == statErr {
@@ -145,6 +242,32 @@ func getStructureForTestsOk(parsedOutput *parsedTestOutput, input_dir string, ve
 		report.Tests = append(report.Tests, test)
 	}
 
+	testsRan := slices.ContainsFunc(parsedOutput.testLines, func(line testLine) bool { return line.Action == "run" })
+	if len(tests) > 0 && !testsRan && packagePanic(parsedOutput.joinPackageMessages("")) != "" {
+		// None of the tests ran because the package panicked outside of a test, e.g. during
+		// initialization. Like without any test results, this is an error of the whole test run.
+		report.Status = statErr
+		solutionDir, _ := filepath.Abs(input_dir)
+		report.Message, report.DataRaces = summarizeDataRacesInMessage(parsedOutput.joinPackageMessages(""), solutionDir)
+	}
+	if parsedOutput.stoppedEarly != "" {
+		// The tests collected so far are kept, the remaining ones are reported as not executed.
+		report.Status = statErr
+		report.Message = parsedOutput.stoppedEarly
+	}
+	if cfg.TaskIDsEnabled {
+		report.Tasks = summarizeTasks(report.Tests)
+	}
+	if cfg.StrictTaskIDs && len(taskIDProblems) > 0 {
+		// The tests are kept in the report, so students still see their results.
+		// A message about the test run, e.g. that it was stopped early, is kept as well.
+		report.Status = statErr
+		if report.Message != "" {
+			report.Message += "\n\n"
+		}
+		report.Message += "Invalid task ID configuration:\n" + strings.Join(taskIDProblems, "\n")
+	}
+
 	return report
 }
 
@@ -152,6 +275,15 @@ type parsedTestOutput struct {
 	testLines        []testLine
 	pkgLevelMessages []string
 	failMessages     []string
+	// pkgLevelMessagesByPackage contains the pkgLevelMessages by the import path of the package.
+	pkgLevelMessagesByPackage map[string][]string
+	// buildOutput contains the compiler output, failedBuilds the
+	// import paths of the packages that failed to build.
+	buildOutput  []string
+	failedBuilds []string
+	// stoppedEarly explains why processing the output was stopped before
+	// `go test` finished, it is empty if all output was processed.
+	stoppedEarly string
 }
 
 func (out *parsedTestOutput) hasFailMessages() bool {
@@ -162,6 +294,10 @@ func (out *parsedTestOutput) joinFailMessages(sep string) string {
 	return strings.Join(out.failMessages, sep)
 }
 
+func (out *parsedTestOutput) hasBuildFailures() bool {
+	return len(out.failedBuilds) > 0
+}
+
 func (out *parsedTestOutput) hasPackageMessages() bool {
 	return len(out.pkgLevelMessages) > 0
 }
@@ -170,70 +306,151 @@ func (out *parsedTestOutput) joinPackageMessages(sep string) string {
 	return strings.Join(out.pkgLevelMessages, sep)
 }
 
-func parseTestOutput(lines bytes.Buffer) (*parsedTestOutput, error) {
+// parseTestOutput reads the output of `go test --json` line by line as it is produced.
+// The amount of output that is kept is restricted by the given limits. If the total
+// output exceeds the limit, reading stops early and stoppedEarly is set.
+func parseTestOutput(r io.Reader, limits OutputLimits) (*parsedTestOutput, error) {
 	parsedOutput := &parsedTestOutput{
 		testLines:        make([]testLine, 0),
 		pkgLevelMessages: make([]string, 0),
 		failMessages:     make([]string, 0),
 	}
 
-	scanner := bufio.NewScanner(&lines)
-	for scanner.Scan() {
-		lineBytes := scanner.Bytes()
-		var line testLine
+	reader := bufio.NewReader(r)
+	outputBytesByTest := make(map[string]int)
+	var total int64
+	for {
+		lineBytes, truncated, consumed, readErr := readLine(reader, limits.MaxLineBytes)
+		total += int64(consumed)
+		if total > limits.MaxOutputBytes {
+			parsedOutput.stoppedEarly = fmt.Sprintf(
+				"The output of the tests exceeded %d bytes, the test run was stopped early.",
+				limits.MaxOutputBytes,
+			)
+			return parsedOutput, nil
+		}
+		if readErr != nil && readErr != io.EOF {
+			return nil, fmt.Errorf("reading test output: %w", readErr)
+		}
 
-		if len(lineBytes) == 0 {
-			continue
+		if len(lineBytes) > 0 {
+			err := parsedOutput.addLine(lineBytes, truncated, limits, outputBytesByTest)
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		if !bytes.HasPrefix(lineBytes, []byte{'{'}) {
-			// if the line is not a json, we need to collect the lines to gather why `go test --json` failed
-			parsedOutput.failMessages = append(parsedOutput.failMessages, string(lineBytes))
-			continue
+		if readErr == io.EOF {
+			return parsedOutput, nil
 		}
+	}
+}
 
-		if err := json.Unmarshal(lineBytes, &line); err != nil {
-			return nil, fmt.Errorf("parsing line starting with '{' as json: %w", err)
+// addLine adds a single line of `go test --json` output to the parsed output.
+// truncated indicates that the line was longer than allowed by the limits.
+func (out *parsedTestOutput) addLine(lineBytes []byte, truncated bool, limits OutputLimits, outputBytesByTest map[string]int) error {
+	if !bytes.HasPrefix(lineBytes, []byte{'{'}) {
+		// if the line is not a json, we need to collect the lines to gather why `go test --json` failed
+		message := string(lineBytes)
+		if truncated {
+			message += " [truncated]"
+		}
+		out.failMessages = append(out.failMessages, message)
+		return nil
+	}
+
+	var line testLine
+	if truncated {
+		line = parseTruncatedLine(lineBytes, limits.MaxLineBytes)
+	} else if err := json.Unmarshal(lineBytes, &line); err != nil {
+		return fmt.Errorf("parsing line starting with '{' as json: %w", err)
+	}
+
+	switch line.Action {
+	case "build-output":
+		out.buildOutput = append(out.buildOutput, line.Output)
+		return nil
+	case "build-fail":
+		out.addFailedBuild(line.ImportPath)
+		return nil
+	case statFail:
+		if line.FailedBuild != "" {
+			out.addFailedBuild(line.FailedBuild)
 		}
+	}
 
-		if line.Test == "" {
-			// We collect messages that do not belong to an individual test and use them later
-			// as error message in case there was no test level message found at all.
-			if line.Output != "" {
-				parsedOutput.pkgLevelMessages = append(parsedOutput.pkgLevelMessages, line.Output)
+	if line.Test == "" {
+		// We collect messages that do not belong to an individual test and use them later
+		// as error message in case there was no test level message found at all.
+		if line.Output != "" {
+			out.pkgLevelMessages = append(out.pkgLevelMessages, line.Output)
+			if out.pkgLevelMessagesByPackage == nil {
+				out.pkgLevelMessagesByPackage = map[string][]string{}
 			}
-			continue
+			out.pkgLevelMessagesByPackage[line.Package] = append(out.pkgLevelMessagesByPackage[line.Package], line.Output)
 		}
+		return nil
+	}
 
-		parsedOutput.testLines = append(parsedOutput.testLines, line)
+	if line.Action == "output" && !capTestOutput(&line, outputBytesByTest, limits.MaxTestOutputBytes) {
+		return nil
 	}
+	out.testLines = append(out.testLines, line)
+	return nil
+}
 
-	return parsedOutput, nil
+func (out *parsedTestOutput) addFailedBuild(importPath string) {
+	if !slices.Contains(out.failedBuilds, importPath) {
+		out.failedBuilds = append(out.failedBuilds, importPath)
+	}
+}
+
+// capTestOutput makes sure the output collected for a single test does not exceed maxBytes.
+// When the limit is reached, the output of the line is replaced with a note once,
+// all later lines for the test should be dropped (false is returned).
+func capTestOutput(line *testLine, outputBytesByTest map[string]int, maxBytes int) bool {
+	key := line.Package + " " + line.Test
+	seen := outputBytesByTest[key]
+	if seen > maxBytes {
+		return false
+	}
+	outputBytesByTest[key] = seen + len(line.Output)
+	if seen+len(line.Output) > maxBytes {
+		line.Output = fmt.Sprintf("[output of the test exceeded %d bytes and was truncated]\n", maxBytes)
+	}
+	return true
 }
 
 func processTestResults(
 	parsedOutput *parsedTestOutput,
-	input_dir string,
+	rootLevelTests []rootLevelTest,
 	taskIDsEnabled bool,
 ) []testResult {
 
 	results := make([]testResult, 0)
 	resultIdxByName := make(map[string]int)
 
-	testFiles := FindTestFiles(input_dir)
-	rootLevelTests := FindAllRootLevelTests(testFiles)
 	rootLevelTestsMap := ConvertToMapByTestName(rootLevelTests)
 
 	for _, parsedLine := range parsedOutput.testLines {
 		switch parsedLine.Action {
 		case "run":
-			tc, taskID := ExtractTestCodeAndTaskID(rootLevelTestsMap, parsedLine.Test)
+			if idx, found := resultIdxByName[parsedLine.Test]; found {
+				// The test runs again because of -count or -cpu. The runs are merged
+				// into one result that only passes if all runs passed.
+				if results[idx].Status != statFail {
+					results[idx].Status = statErr
+				}
+				continue
+			}
+			tc, taskID, taskIDErr := ExtractTestCodeAndTaskID(rootLevelTestsMap, parsedLine.Test)
 			result := testResult{
 				Name: parsedLine.Test,
 				// Use error as default state in case no other state is found later.
 				// No state is provided e.g. when there is a stack overflow.
-				Status: statErr,
-				TaskID: taskID,
+				Status:    statErr,
+				TaskID:    taskID,
+				taskIDErr: taskIDErr,
 			}
 			if len(tc) > 0 {
 				result.TestCode = tc
@@ -251,20 +468,27 @@ func processTestResults(
 		case statFail:
 			if idx, found := resultIdxByName[parsedLine.Test]; found {
 				results[idx].Status = statFail
+				results[idx].DurationMs += elapsedMs(parsedLine.Elapsed)
 			} else {
 				log.Printf("cannot set failed status for unknown test: %s\n", parsedLine.Test)
 				continue
 			}
 		case statPass:
 			if idx, found := resultIdxByName[parsedLine.Test]; found {
-				results[idx].Status = statPass
+				if results[idx].Status != statFail {
+					results[idx].Status = statPass
+				}
+				results[idx].DurationMs += elapsedMs(parsedLine.Elapsed)
 			} else {
 				log.Printf("cannot set passing status for unknown test: %s\n", parsedLine.Test)
 				continue
 			}
 		case statSkip:
 			if idx, found := resultIdxByName[parsedLine.Test]; found {
-				results[idx].Status = statSkip
+				if results[idx].Status != statFail {
+					results[idx].Status = statSkip
+				}
+				results[idx].DurationMs += elapsedMs(parsedLine.Elapsed)
 			} else {
 				log.Printf("cannot set skipped status for unknown test: %s\n", parsedLine.Test)
 				continue
@@ -272,19 +496,36 @@ func processTestResults(
 		}
 	}
 
-	if taskIDsEnabled {
+	// The test binary stopped before all tests ran if a test or the package panicked
+	// or if reading the output was stopped.
+	aborted := parsedOutput.stoppedEarly != "" || testPanicked(results)
+	notExecutedMsg := notExecutedMessage
+	if trace := packagePanic(parsedOutput.joinPackageMessages("")); trace != "" {
+		results, notExecutedMsg = attributePackagePanic(results, runningTests(parsedOutput.testLines), trace)
+		aborted = true
+	}
+
+	if taskIDsEnabled || aborted {
 		// We only need this for the V3 UI with task ids.
 		// It causes issues for some practice exercises.
-		results = addNonExecutedTests(rootLevelTests, results)
+		// If the test binary stopped early, we also need it to show which tests did not run.
+		results = addNonExecutedTests(rootLevelTests, results, notExecutedMsg)
 	}
 
 	return results
 }
 
+// elapsedMs converts the elapsed time of a test in seconds to milliseconds.
+func elapsedMs(elapsed float64) int64 {
+	return int64(math.Round(elapsed * 1000))
+}
+
+const notExecutedMessage = "This test was not executed."
+
 // addNonExecutedTests adds tests to the result set that were not executed.
-// They are added with status "error" and special message (this is common in other tracks as well).
+// They are added with status "error" and the given message (this is common in other tracks as well).
 // The function makes sure that the result for non-executed test is inserted in the correct position.
-func addNonExecutedTests(rootLevelTests []rootLevelTest, results []testResult) []testResult {
+func addNonExecutedTests(rootLevelTests []rootLevelTest, results []testResult, message string) []testResult {
 	insertResultAfterIdx := -1
 	for parentIdx, parentTest := range rootLevelTests {
 		parentFound := false
@@ -312,7 +553,10 @@ func addNonExecutedTests(rootLevelTests []rootLevelTest, results []testResult) [
 			Name:     parentTest.name,
 			Status:   statErr,
 			TestCode: parentTest.code,
-			Message:  "This test was not executed.",
+			Message:  message,
+			TaskID:   parentTest.taskID,
+
+			taskIDErr: parentTest.taskIDErr,
 		}
 
 		if insertResultAfterIdx < 0 {
@@ -329,6 +573,24 @@ func addNonExecutedTests(rootLevelTests []rootLevelTest, results []testResult) [
 	return results
 }
 
+var testOutputLocation = regexp.MustCompile(`^\S+\.go:[0-9]+: `)
+
+// skipReason extracts the message passed to t.Skip from the output of a skipped test.
+func skipReason(message string) string {
+	var reason []string
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "=== RUN") || strings.HasPrefix(line, "--- SKIP") {
+			continue
+		}
+		reason = append(reason, testOutputLocation.ReplaceAllString(line, ""))
+	}
+	if len(reason) == 0 {
+		return "This test was skipped."
+	}
+	return strings.Join(reason, "\n")
+}
+
 var parentTestMsg = regexp.MustCompile(`(?s)=== RUN\s*Test.*--- (?:FAIL|PASS): Test.*? \(.*?\)\s(.*)`)
 
 // removeObsoleteParentTests cleans up the list of test results. The parent test
@@ -426,11 +688,13 @@ func cleanUpTaskIDs(tests []testResult, taskIDsEnabled bool) []testResult {
 
 	// No explicit task IDs found, performing auto-assignment.
 	currentParent := ""
+	currentPackage := ""
 	currentTaskID := uint64(0)
 	for i := range tests {
 		parentName, _ := splitTestName(tests[i].Name)
-		if parentName != currentParent {
+		if parentName != currentParent || tests[i].packagePath != currentPackage {
 			currentParent = parentName
+			currentPackage = tests[i].packagePath
 			// Only increment the number, if a new parent test starts.
 			currentTaskID++
 		}
@@ -440,73 +704,41 @@ func cleanUpTaskIDs(tests []testResult, taskIDsEnabled bool) []testResult {
 	return tests
 }
 
-// codeCompiles runs "go build ." and return whether it worked or not
-func codeCompiles(input_dir string) bool {
-	goExe, err := exec.LookPath("go")
-	if err != nil {
-		log.Fatal("failed to find go executable: ", err)
-	}
-
-	var stdout, stderr bytes.Buffer
-	testCmd := &exec.Cmd{
-		Dir:    input_dir,
-		Path:   goExe,
-		Args:   []string{goExe, "build", "."},
-		Stdout: &stdout,
-		Stderr: &stderr,
-	}
-
-	err = testCmd.Run()
-	return err == nil
-}
-
-// testCompiles compiles the tests and return whether it worked or not
-func testCompiles(input_dir string) bool {
-	goExe, err := exec.LookPath("go")
-	if err != nil {
-		log.Fatal("failed to find go executable: ", err)
-	}
-
-	var stdout, stderr bytes.Buffer
-	testCmd := &exec.Cmd{
-		Dir:  input_dir,
-		Path: goExe,
-		// "Official" recommendation for compiling but not running the tests
-		// https://github.com/golang/go/issues/46712#issuecomment-859949958
-		Args:   []string{goExe, "test", "-c", "-o", os.DevNull},
-		Stdout: &stdout,
-		Stderr: &stderr,
-	}
-
-	err = testCmd.Run()
-	return err == nil
-}
-
-// Run the "go test --short --json ." command, return output
+// Run the "go test --short --json ." command, return the parsed output
 // --short is used to exclude benchmark tests, given the spec / web UI currently cannot handle them
-func runTests(input_dir string, additionalTestFlags []string) (bytes.Buffer, bool) {
-	goExe, err := exec.LookPath("go")
+func runTests(input_dir string, cfg ExerciseConfig, limits OutputLimits) (*parsedTestOutput, bool) {
+	testArgs := []string{"test", "--short", "--json"}
+	testArgs = append(testArgs, cfg.TestingFlags...)
+	testArgs = append(testArgs, limitTestFlags(cfg)...)
+	testArgs = append(testArgs, testPattern(cfg))
+
+	var stderr bytes.Buffer
+	testCmd := goCommand(input_dir, cfg, testArgs...)
+	testCmd.Stderr = &stderr
+	startProcessGroup(testCmd)
+	stdout, err := testCmd.StdoutPipe()
 	if err != nil {
-		log.Fatal("failed to find go executable: ", err)
+		log.Fatalf("error: failed to connect to stdout of '%s': %s", testCmd.String(), err)
+	}
+	if err := testCmd.Start(); err != nil {
+		log.Fatalf("error: failed to start '%s': %s", testCmd.String(), err)
 	}
 
-	testCommand := []string{goExe, "test", "--short", "--json"}
-	testCommand = append(testCommand, additionalTestFlags...)
-	testCommand = append(testCommand, ".")
-
-	var stdout, stderr bytes.Buffer
-	testCmd := &exec.Cmd{
-		Dir:    input_dir,
-		Path:   goExe,
-		Args:   testCommand,
-		Stdout: &stdout,
-		Stderr: &stderr,
+	parsedOutput, err := parseTestOutput(stdout, limits)
+	if err != nil || parsedOutput.stoppedEarly != "" {
+		// The remaining output is not needed, stop the tests including the test binaries.
+		_ = killProcessGroup(testCmd)
+		_ = testCmd.Wait()
+		if err != nil {
+			log.Fatalf("parsing test output: %s", err)
+		}
+		return parsedOutput, true
 	}
 
-	err = testCmd.Run()
+	err = testCmd.Wait()
 	if err == nil {
 		// Test ran without any problems, return json
-		return stdout, true
+		return parsedOutput, true
 	}
 
 	exitError, ok := err.(*exec.ExitError)
@@ -517,74 +749,31 @@ func runTests(input_dir string, additionalTestFlags []string) (bytes.Buffer, boo
 	}
 	exc := exitError.ExitCode()
 
-	// Do the code and the test even compile?
-	if !codeCompiles(input_dir) || !testCompiles(input_dir) {
+	// Do the code and the test even compile? The compiler output is part of the json output,
+	// only errors of the go command itself (e.g. an invalid go.mod file) end up in stderr.
+	if parsedOutput.hasBuildFailures() || (len(parsedOutput.testLines) == 0 && stderr.Len() > 0) {
 		// Combine stderr and stdout in the same order in which they
 		// show up in the console.
-		stderr.WriteString(stdout.String())
-		fmt.Fprintf(&stderr, "'%s' returned exit code %d: %s", testCmd.String(), exc, err)
-		return stderr, false
+		var failMessages []string
+		for _, line := range strings.Split(stderr.String(), "\n") {
+			if line != "" {
+				failMessages = append(failMessages, line)
+			}
+		}
+		failMessages = append(failMessages, parsedOutput.failMessages...)
+		failMessages = append(failMessages, fmt.Sprintf("'%s' returned exit code %d: %s", commandLine(testCmd), exc, err))
+		parsedOutput.failMessages = failMessages
+		return parsedOutput, false
 	}
 
 	switch exc {
 	case 1:
 		// `go test` returns 1 when tests fail, this is fine
-		return stdout, true
+		return parsedOutput, true
 	default:
 		log.Fatalf("error: '%s' failed with exit error %d: %s",
 			testCmd.String(), exc, err,
 		)
 	}
-	return stdout, false
-}
-
-type config struct {
-	Custom ExerciseConfig `json:"custom"`
-}
-
-type ExerciseConfig struct {
-	TestingFlags   []string `json:"testingFlags"`
-	TaskIDsEnabled bool     `json:"taskIdsEnabled"`
-}
-
-func parseExerciseConfig(input_dir string) ExerciseConfig {
-	configContent, err := os.ReadFile(filepath.Join(input_dir, ".meta", "config.json"))
-	if err != nil {
-		log.Printf("warning: config.json could not be read: %v", err)
-		return ExerciseConfig{}
-	}
-
-	cfg := &config{}
-	err = json.Unmarshal(configContent, cfg)
-	if err != nil {
-		log.Printf("failed to parse config.json: %v", err)
-		return ExerciseConfig{}
-	}
-
-	if len(cfg.Custom.TestingFlags) != 0 {
-		cfg.Custom.TestingFlags = validateTestingFlags(cfg.Custom.TestingFlags)
-	}
-
-	return cfg.Custom
-}
-
-func validateTestingFlags(flags []string) []string {
-	var validFlags []string
-	for _, flag := range flags {
-		if contains(allowedTestingFlags, flag) {
-			validFlags = append(validFlags, flag)
-		} else {
-			log.Printf("invalid testing flag found in config.json: %s", flag)
-		}
-	}
-	return validFlags
-}
-
-func contains(list []string, target string) bool {
-	for _, item := range list {
-		if item == target {
-			return true
-		}
-	}
-	return false
+	return parsedOutput, false
 }
@@ -21,17 +21,12 @@ const version = 3
 func TestRunTests_RuntimeError(t *testing.T) {
 	input_dir := filepath.Join("testdata", "practice", "runtime_error")
 
-	cmdres, ok := runTests(input_dir, nil)
+	testOutput, ok := runTests(input_dir, ExerciseConfig{}, DefaultOutputLimits)
 	if !ok {
-		fmt.Printf("runtime error test expected to return ok: %s", cmdres.String())
+		fmt.Printf("runtime error test expected to return ok: %s", testOutput.joinFailMessages("\n"))
 	}
 
-	testOutput, err := parseTestOutput(cmdres)
-	if err != nil {
-		t.Fatalf("parsing test output: %s", err)
-	}
-
-	report := getStructureForTestsOk(testOutput, input_dir, version, false)
+	report := getStructureForTestsOk(testOutput, input_dir, version, ExerciseConfig{})
 
 	jsonBytes, err := json.MarshalIndent(report, "", "\t")
 	if err != nil {
@@ -48,7 +43,7 @@ func TestRunTests_RuntimeError(t *testing.T) {
 			"name": "TestAddGigasecond",
 			"status": "error",
 			"test_code": "func TestAddGigasecond(t *testing.T) {\n\tinput, _ := time.Parse(\"2006-01-02\", \"2011-04-25\")\n\tAddGigasecond(input)\n}",
-			"message": "\n=== RUN   TestAddGigasecond\n\nruntime: goroutine stack exceeds`
+			"message": "\n=== RUN   TestAddGigasecond\n\nfatal error: stack overflow at runtime_error.go:9\n"`
 
 	if !strings.HasPrefix(result, pre) {
 		t.Errorf("runtime error result has unexpected json prefix: %s", result)
@@ -58,17 +53,12 @@ func TestRunTests_RuntimeError(t *testing.T) {
 func TestRunTests_RaceDetector(t *testing.T) {
 
 	input_dir := filepath.Join("testdata", "practice", "race")
-	cmdres, ok := runTests(input_dir, []string{"-race"})
+	testOutput, ok := runTests(input_dir, ExerciseConfig{TestingFlags: []string{"-race"}}, DefaultOutputLimits)
 	if !ok {
-		fmt.Printf("race detector test expected to return ok: %s", cmdres.String())
+		fmt.Printf("race detector test expected to return ok: %s", testOutput.joinFailMessages("\n"))
 	}
 
-	testOutput, err := parseTestOutput(cmdres)
-	if err != nil {
-		t.Errorf("parsing test output: %s", err)
-	}
-
-	report := getStructureForTestsOk(testOutput, input_dir, version, false)
+	report := getStructureForTestsOk(testOutput, input_dir, version, ExerciseConfig{})
 	if report.Status != "fail" {
 		t.Errorf("wrong status for race detector test: got %q, want %q", report.Status, "fail")
 	}
@@ -78,6 +68,151 @@ func TestRunTests_RaceDetector(t *testing.T) {
 	}
 }
 
+func TestParseTestOutput_Limits(t *testing.T) {
+	output := strings.Join([]string{
+		`{"Action":"run","Test":"TestA"}`,
+		`{"Action":"output","Test":"TestA","Output":"=== RUN   TestA\n"}`,
+		`{"Action":"output","Test":"TestA","Output":"` + strings.Repeat("x", 100) + `\n"}`,
+		`{"Action":"output","Test":"TestA","Output":"first\n"}`,
+		`{"Action":"output","Test":"TestA","Output":"second\n"}`,
+		`{"Action":"output","Test":"TestA","Output":"third\n"}`,
+		`{"Action":"fail","Test":"TestA","Elapsed":0.01}`,
+		`{"Action":"run","Test":"TestB"}`,
+		`{"Action":"output","Test":"TestB","Output":"=== RUN   TestB\n"}`,
+	}, "\n")
+
+	tests := []struct {
+		name         string
+		limits       OutputLimits
+		outputs      []string
+		stoppedEarly bool
+	}{
+		{
+			name:    "everything within limits",
+			limits:  DefaultOutputLimits,
+			outputs: []string{"=== RUN   TestA\n", strings.Repeat("x", 100) + "\n", "first\n", "second\n", "third\n", "=== RUN   TestB\n"},
+		},
+		{
+			name:   "long line is truncated",
+			limits: OutputLimits{MaxLineBytes: 80, MaxTestOutputBytes: 1000, MaxOutputBytes: 1000},
+			outputs: []string{
+				"=== RUN   TestA\n",
+				"[output line longer than 80 bytes was truncated]\n",
+				"first\n", "second\n", "third\n", "=== RUN   TestB\n",
+			},
+		},
+		{
+			name:   "test output is capped",
+			limits: OutputLimits{MaxLineBytes: 1000, MaxTestOutputBytes: 120, MaxOutputBytes: 1000},
+			outputs: []string{
+				"=== RUN   TestA\n",
+				strings.Repeat("x", 100) + "\n",
+				"[output of the test exceeded 120 bytes and was truncated]\n",
+				"=== RUN   TestB\n",
+			},
+		},
+		{
+			name:         "total output exceeded",
+			limits:       OutputLimits{MaxLineBytes: 1000, MaxTestOutputBytes: 1000, MaxOutputBytes: 200},
+			outputs:      []string{"=== RUN   TestA\n"},
+			stoppedEarly: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseTestOutput(strings.NewReader(output), tt.limits)
+			if err != nil {
+				t.Fatalf("parsing test output: %s", err)
+			}
+			var outputs []string
+			for _, line := range parsed.testLines {
+				if line.Action == "output" {
+					outputs = append(outputs, line.Output)
+				}
+			}
+			assert.Equal(t, tt.outputs, outputs)
+			assert.Equal(t, tt.stoppedEarly, parsed.stoppedEarly != "")
+		})
+	}
+}
+
+func TestRunTests_OutputLimit(t *testing.T) {
+	input_dir := filepath.Join("testdata", "practice", "passing")
+	limits := OutputLimits{MaxLineBytes: 1000, MaxTestOutputBytes: 1000, MaxOutputBytes: 300}
+
+	testOutput, ok := runTests(input_dir, ExerciseConfig{}, limits)
+	assert.True(t, ok)
+
+	report := getStructureForTestsOk(testOutput, input_dir, version, ExerciseConfig{})
+	assert.Equal(t, statErr, report.Status)
+	assert.Equal(t, "The output of the tests exceeded 300 bytes, the test run was stopped early.", report.Message)
+	assert.NotEmpty(t, report.Tests)
+}
+
+func TestRunTests_OutputLimitWithStrictTaskIDs(t *testing.T) {
+	input_dir := filepath.Join("testdata", "concept", "strict_task_ids")
+	limits := OutputLimits{MaxLineBytes: 1000, MaxTestOutputBytes: 1000, MaxOutputBytes: 300}
+	cfg := ExerciseConfig{TaskIDsEnabled: true, StrictTaskIDs: true}
+
+	testOutput, ok := runTests(input_dir, cfg, limits)
+	assert.True(t, ok)
+
+	report := getStructureForTestsOk(testOutput, input_dir, version, cfg)
+	assert.Equal(t, statErr, report.Status)
+	assert.True(t, strings.HasPrefix(report.Message, "The output of the tests exceeded 300 bytes, the test run was stopped early.\n\nInvalid task ID configuration:\n"),
+		"unexpected message: %s", report.Message)
+}
+
+func TestParseTestOutput_BuildFailure(t *testing.T) {
+	output := strings.Join([]string{
+		`{"ImportPath":"gigasecond [gigasecond.test]","Action":"build-output","Output":"# gigasecond [gigasecond.test]\n"}`,
+		`{"ImportPath":"gigasecond [gigasecond.test]","Action":"build-output","Output":"./broken.go:11:2: undefined: unknownVar\n"}`,
+		`{"ImportPath":"gigasecond [gigasecond.test]","Action":"build-fail"}`,
+		`{"Action":"start","Package":"gigasecond"}`,
+		`{"Action":"output","Package":"gigasecond","Output":"FAIL\tgigasecond [build failed]\n"}`,
+		`{"Action":"fail","Package":"gigasecond","Elapsed":0,"FailedBuild":"gigasecond [gigasecond.test]"}`,
+	}, "\n")
+
+	parsed, err := parseTestOutput(strings.NewReader(output), DefaultOutputLimits)
+	if err != nil {
+		t.Fatalf("parsing test output: %s", err)
+	}
+
+	assert.True(t, parsed.hasBuildFailures())
+	assert.Equal(t, []string{"gigasecond [gigasecond.test]"}, parsed.failedBuilds)
+	assert.Equal(t, []string{"# gigasecond [gigasecond.test]\n", "./broken.go:11:2: undefined: unknownVar\n"}, parsed.buildOutput)
+	assert.Equal(t, []string{"FAIL\tgigasecond [build failed]\n"}, parsed.pkgLevelMessages)
+	assert.Empty(t, parsed.failMessages)
+
+	report := getStructureForTestsNotOk(parsed, filepath.Join("testdata", "practice", "broken"), version, ExerciseConfig{})
+	assert.Equal(t, "# gigasecond [gigasecond.test]\n\n./broken.go:11:2: undefined: unknownVar\n\nFAIL\tgigasecond [build failed]\n", report.Message)
+}
+
+func TestProcessTestResults_RepeatedRuns(t *testing.T) {
+	output := strings.Join([]string{
+		`{"Action":"run","Package":"leap","Test":"TestLeap"}`,
+		`{"Action":"fail","Package":"leap","Test":"TestLeap","Elapsed":0.01}`,
+		`{"Action":"run","Package":"leap","Test":"TestLeap"}`,
+		`{"Action":"pass","Package":"leap","Test":"TestLeap","Elapsed":0.02}`,
+		`{"Action":"run","Package":"leap","Test":"TestNonLeap"}`,
+		`{"Action":"pass","Package":"leap","Test":"TestNonLeap","Elapsed":0.01}`,
+		`{"Action":"run","Package":"leap","Test":"TestNonLeap"}`,
+		`{"Action":"pass","Package":"leap","Test":"TestNonLeap","Elapsed":0.01}`,
+	}, "\n")
+
+	parsed, err := parseTestOutput(strings.NewReader(output), DefaultOutputLimits)
+	if err != nil {
+		t.Fatalf("parsing test output: %s", err)
+	}
+
+	results := processTestResults(parsed, nil, false)
+	assert.Equal(t, []testResult{
+		{Name: "TestLeap", Status: statFail, DurationMs: 30},
+		{Name: "TestNonLeap", Status: statPass, DurationMs: 20},
+	}, results)
+}
+
 func TestAddNonExecutedTests(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -205,8 +340,38 @@ func TestAddNonExecutedTests(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := addNonExecutedTests(tt.inputRootLevelTests, tt.inputResults)
+			result := addNonExecutedTests(tt.inputRootLevelTests, tt.inputResults, notExecutedMessage)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
 }
+
+func TestSkipReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{
+			name:     "skip with reason",
+			message:  "\n=== RUN   TestConcDeposit\n\n    bank_account_test.go:13: Multiple CPU cores required for concurrency tests.\n\n--- SKIP: TestConcDeposit (0.00s)\n",
+			expected: "Multiple CPU cores required for concurrency tests.",
+		},
+		{
+			name:     "skip with multi-line reason",
+			message:  "\n=== RUN   TestX\n\n    x_test.go:5: first line\n\n    second line\n\n--- SKIP: TestX (0.00s)\n",
+			expected: "first line\nsecond line",
+		},
+		{
+			name:     "skip without reason",
+			message:  "\n=== RUN   TestX\n\n--- SKIP: TestX (0.00s)\n",
+			expected: "This test was skipped.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, skipReason(tt.message))
+		})
+	}
+}
@@ -41,19 +41,31 @@ func FindTestFiles(codePath string) []string {
 	return found
 }
 
-// return the associated test function code from the given test file
-func ExtractTestCodeAndTaskID(rootLevelTests map[string]rootLevelTest, testName string) (string, uint64) {
+// return the associated test function code from the given test file, together with the
+// task ID of the test and the error found when parsing the task ID annotation (if any).
+// A task ID set on the test data entry of a subtest takes precedence over the one of the parent test.
+func ExtractTestCodeAndTaskID(rootLevelTests map[string]rootLevelTest, testName string) (string, uint64, error) {
 	test, subtest := splitTestName(testName)
 	rootLevelTest := rootLevelTests[test]
 	if len(subtest) == 0 {
-		return rootLevelTest.code, rootLevelTest.taskID
+		return rootLevelTest.code, rootLevelTest.taskID, rootLevelTest.taskIDErr
 	}
 	defer handleASTPanic()
-	subtc := getSubCode(test, subtest, rootLevelTest.code, rootLevelTest.fileName, rootLevelTest.pkgName)
+	subtc, subTaskID, err := getSubCode(test, subtest, rootLevelTest.code, rootLevelTest.fileName, rootLevelTest.pkgName)
+	taskID := rootLevelTest.taskID
+	if subTaskID != 0 {
+		taskID = subTaskID
+	}
+	if err != nil {
+		// The invalid task ID was already logged by getSubCode.
+		err = fmt.Errorf("test data entry %q: %w", subtest, err)
+	} else {
+		err = rootLevelTest.taskIDErr
+	}
 	if len(subtc) == 0 {
-		return rootLevelTest.code, rootLevelTest.taskID
+		return rootLevelTest.code, taskID, err
 	}
-	return subtc, rootLevelTest.taskID
+	return subtc, taskID, err
 }
 
 func handleASTPanic() {
@@ -214,7 +214,7 @@ func TestExtractTestCode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			code, _ := ExtractTestCodeAndTaskID(rootLevelTestsMap, tt.testName)
+			code, _, _ := ExtractTestCodeAndTaskID(rootLevelTestsMap, tt.testName)
 
 			actualLines := strings.Split(code, "\n")
 			expectedLines := strings.Split(tt.code, "\n")
@@ -242,3 +242,48 @@ func TestExtractTestCode(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractTaskIDForSubtests(t *testing.T) {
+	tf := filepath.Join("testdata", "concept", "subtest_task_ids", "conditionals_test.go")
+	rootLevelTestsMap := ConvertToMapByTestName(FindAllRootLevelTests([]string{tf}))
+	tests := []struct {
+		name     string
+		testName string
+		taskID   uint64
+	}{
+		{
+			name:     "entry without annotation uses task id of parent test",
+			testName: "TestParseCard/parse_two",
+			taskID:   1,
+		},
+		{
+			name:     "comment before entry",
+			testName: "TestParseCard/parse_jack",
+			taskID:   2,
+		},
+		{
+			name:     "comment inside entry",
+			testName: "TestParseCard/parse_king",
+			taskID:   2,
+		},
+		{
+			name:     "taskID field",
+			testName: "TestBlackjack/no_blackjack",
+			taskID:   4,
+		},
+		{
+			name:     "no task id at all",
+			testName: "TestBlackjack",
+			taskID:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, taskID, err := ExtractTestCodeAndTaskID(rootLevelTestsMap, tt.testName)
+			if taskID != tt.taskID || err != nil {
+				t.Errorf("ExtractTestCodeAndTaskID(%v) returned task id %d, %v; want %d, <nil>",
+					tt.testName, taskID, err, tt.taskID)
+			}
+		})
+	}
+}
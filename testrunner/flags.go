@@ -0,0 +1,149 @@
+package testrunner
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// testingFlagPolicy describes a testing flag that exercises may pass to `go test`.
+type testingFlagPolicy struct {
+	// boolean is set for flags that do not need a value, e.g. -race.
+	boolean bool
+	// validate checks the value of a flag that is passed as -name=value.
+	validate func(value string) error
+}
+
+// For security reasons, only testing flags that are included in the list below are processed.
+// Flags that take a value need to be passed in the form -name=value.
+var allowedTestingFlags = map[string]testingFlagPolicy{
+	"-race": {boolean: true},
+	// Repeated runs of a test because of -count or -cpu are merged into a single result.
+	"-count":   {validate: intInRange(1, 10)},
+	"-timeout": {validate: durationInRange(minTimeout, maxTimeout)},
+	"-cpu":     {validate: listOf(intInRange(1, 8), 4)},
+	"-shuffle": {validate: shuffleValue},
+	"-tags":    {validate: listOf(matching(buildTag), 10)},
+	"-run":     {validate: runPattern},
+}
+
+var buildTag = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+const maxRunPatternLength = 200
+
+// validateTestingFlags returns the flags that are allowed, normalized to a single
+// leading dash, and a warning for every flag that was rejected.
+func validateTestingFlags(flags []string) ([]string, []testWarning) {
+	var validFlags []string
+	var warnings []testWarning
+	for _, flag := range flags {
+		normalized, err := validateTestingFlag(flag)
+		if err != nil {
+			log.Printf("warning: invalid testing flag %s: %s", flag, err)
+			warnings = append(warnings, testWarning{
+				Kind:    warnTestingFlag,
+				Message: fmt.Sprintf("testing flag %q was ignored: %s", flag, err),
+			})
+			continue
+		}
+		validFlags = append(validFlags, normalized)
+	}
+	return validFlags, warnings
+}
+
+func validateTestingFlag(flag string) (string, error) {
+	name, value, hasValue := strings.Cut(flag, "=")
+	if strings.HasPrefix(name, "--") {
+		name = name[1:]
+	}
+	policy, ok := allowedTestingFlags[name]
+	if !ok {
+		return "", fmt.Errorf("flag %s is not allowed", name)
+	}
+
+	if policy.boolean {
+		if !hasValue {
+			return name, nil
+		}
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "", fmt.Errorf("flag %s only accepts true or false", name)
+		}
+		return name + "=" + value, nil
+	}
+
+	if !hasValue {
+		return "", fmt.Errorf("flag %s needs a value, use %s=value", name, name)
+	}
+	if err := policy.validate(value); err != nil {
+		return "", fmt.Errorf("invalid value for flag %s: %w", name, err)
+	}
+	return name + "=" + value, nil
+}
+
+func intInRange(minValue, maxValue int) func(string) error {
+	return func(value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < minValue || n > maxValue {
+			return fmt.Errorf("%q must be a number between %d and %d", value, minValue, maxValue)
+		}
+		return nil
+	}
+}
+
+func durationInRange(minValue, maxValue time.Duration) func(string) error {
+	return func(value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < minValue || d > maxValue {
+			return fmt.Errorf("%q must be a duration between %s and %s", value, minValue, maxValue)
+		}
+		return nil
+	}
+}
+
+func matching(pattern *regexp.Regexp) func(string) error {
+	return func(value string) error {
+		if !pattern.MatchString(value) {
+			return fmt.Errorf("%q must match %s", value, pattern)
+		}
+		return nil
+	}
+}
+
+// listOf validates a comma separated list of at most maxItems values.
+func listOf(validate func(string) error, maxItems int) func(string) error {
+	return func(value string) error {
+		items := strings.Split(value, ",")
+		if len(items) > maxItems {
+			return fmt.Errorf("at most %d values are allowed", maxItems)
+		}
+		for _, item := range items {
+			if err := validate(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func shuffleValue(value string) error {
+	if value == "on" || value == "off" {
+		return nil
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+		return fmt.Errorf("%q must be on, off or a number", value)
+	}
+	return nil
+}
+
+func runPattern(value string) error {
+	if len(value) > maxRunPatternLength {
+		return fmt.Errorf("the pattern must not be longer than %d characters", maxRunPatternLength)
+	}
+	if _, err := regexp.Compile(value); err != nil {
+		return fmt.Errorf("%q is not a valid regular expression", value)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTestingFlags(t *testing.T) {
+	tests := []struct {
+		name             string
+		flags            []string
+		expectedFlags    []string
+		expectedWarnings []string
+	}{
+		{
+			name:          "allowed flags",
+			flags:         []string{"-race", "-count=1", "-timeout=30s", "-cpu=1,2", "-shuffle=on", "-tags=integration,go1.22", "-run=TestParse/ace"},
+			expectedFlags: []string{"-race", "-count=1", "-timeout=30s", "-cpu=1,2", "-shuffle=on", "-tags=integration,go1.22", "-run=TestParse/ace"},
+		},
+		{
+			name:          "double dash and boolean values",
+			flags:         []string{"--race", "-race=false", "--shuffle=42"},
+			expectedFlags: []string{"-race", "-race=false", "-shuffle=42"},
+		},
+		{
+			name:  "unknown flags",
+			flags: []string{"-v", "-exec=rm", "-race"},
+			expectedWarnings: []string{
+				`testing flag "-v" was ignored: flag -v is not allowed`,
+				`testing flag "-exec=rm" was ignored: flag -exec is not allowed`,
+			},
+			expectedFlags: []string{"-race"},
+		},
+		{
+			name:  "invalid values",
+			flags: []string{"-count", "-count=100", "-timeout=1h", "-cpu=1,2,3,4,5", "-tags=a b", "-run=Test(", "-race=maybe"},
+			expectedWarnings: []string{
+				`testing flag "-count" was ignored: flag -count needs a value, use -count=value`,
+				`testing flag "-count=100" was ignored: invalid value for flag -count: "100" must be a number between 1 and 10`,
+				`testing flag "-timeout=1h" was ignored: invalid value for flag -timeout: "1h" must be a duration between 1s and 5m0s`,
+				`testing flag "-cpu=1,2,3,4,5" was ignored: invalid value for flag -cpu: at most 4 values are allowed`,
+				`testing flag "-tags=a b" was ignored: invalid value for flag -tags: "a b" must match ^[A-Za-z0-9_.]+$`,
+				`testing flag "-run=Test(" was ignored: invalid value for flag -run: "Test(" is not a valid regular expression`,
+				`testing flag "-race=maybe" was ignored: flag -race only accepts true or false`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, warnings := validateTestingFlags(tt.flags)
+			assert.Equal(t, tt.expectedFlags, flags)
+			var messages []string
+			for _, warning := range warnings {
+				assert.Equal(t, warnTestingFlag, warning.Kind)
+				messages = append(messages, warning.Message)
+			}
+			assert.Equal(t, tt.expectedWarnings, messages)
+		})
+	}
+}
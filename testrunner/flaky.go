@@ -0,0 +1,212 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// flakyCheckCounts are the values of the -count flag used for the runs of the flaky check,
+// one after another. Running the tests more than once in the same process reveals tests
+// that depend on global state.
+var flakyCheckCounts = []int{1, 2, 3}
+
+// shuffleSeed matches the output of a test binary that runs the tests in random order.
+var shuffleSeed = regexp.MustCompile(`^-test\.shuffle (-?[0-9]+)`)
+
+// flakyReport is the result of the flaky check.
+type flakyReport struct {
+	// Status is pass if every test had the same outcome in all runs, fail if some tests are
+	// flaky and error if the tests could not be run.
+	Status     string      `json:"status"`
+	Message    string      `json:"message,omitempty"`
+	Runs       []flakyRun  `json:"runs"`
+	FlakyTests []flakyTest `json:"flaky_tests"`
+}
+
+// flakyRun describes a single run of the tests.
+type flakyRun struct {
+	Count int `json:"count"`
+	// Seeds are the shuffle seeds by package.
+	Seeds map[string]int64 `json:"seeds"`
+	// Failed is the number of failed test executions.
+	Failed int `json:"failed"`
+}
+
+// flakyTest is a test that passed in some executions and failed in others.
+type flakyTest struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	Passed  int    `json:"passed"`
+	Failed  int    `json:"failed"`
+	// Failures contain the commands that reproduce the failures.
+	Failures []flakyFailure `json:"failures"`
+}
+
+type flakyFailure struct {
+	Seed    int64  `json:"seed"`
+	Count   int    `json:"count"`
+	Command string `json:"command"`
+}
+
+// CheckFlakiness runs the tests of the solution the given number of times in random order
+// with -shuffle=on and varying -count and reports the tests whose outcome varies.
+// It is meant for maintainers checking that the tests of an exercise are deterministic.
+func CheckFlakiness(input_dir string, opts Options, runs int) []byte {
+	cfg, _ := loadExerciseConfig(input_dir, opts.Config)
+	limits := opts.OutputLimits
+	if limits.MaxOutputBytes == 0 {
+		limits.MaxOutputBytes = cfg.MaxOutputBytes
+	}
+	solutionOverlay := prepareOverlay(input_dir, &cfg)
+	defer solutionOverlay.remove()
+
+	report := flakyReport{Status: statPass, Runs: []flakyRun{}, FlakyTests: []flakyTest{}}
+	var outcomes testOutcomes
+	for i := range runs {
+		count := flakyCheckCounts[i%len(flakyCheckCounts)]
+		runCfg := cfg
+		runCfg.TestingFlags = append(withoutShuffleFlags(cfg.TestingFlags), "-shuffle=on", fmt.Sprintf("-count=%d", count))
+
+		testOutput, ok := runTests(input_dir, runCfg, limits.withDefaults())
+		if !ok {
+			report.Status = statErr
+			report.Message = getStructureForTestsNotOk(testOutput, input_dir, reportVersion, cfg).Message
+			break
+		}
+		if testOutput.stoppedEarly != "" {
+			report.Status = statErr
+			report.Message = testOutput.stoppedEarly
+			break
+		}
+		report.Runs = append(report.Runs, outcomes.add(testOutput, count))
+	}
+	if report.Status == statPass {
+		report.FlakyTests = outcomes.flaky(reproduceCommand(cfg), testPattern(cfg))
+		if len(report.FlakyTests) > 0 {
+			report.Status = statFail
+		}
+	}
+	report.Message = newPathNormalizer(input_dir).normalize(report.Message)
+
+	bts, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		log.Fatalf("Failed to marshal the flaky check report: %s", err)
+	}
+	return bts
+}
+
+// testOutcomes collects the outcomes of the tests over all runs in the order the tests
+// were first seen.
+type testOutcomes []*flakyTest
+
+// add records the outcomes of a single run.
+func (outcomes *testOutcomes) add(testOutput *parsedTestOutput, count int) flakyRun {
+	run := flakyRun{Count: count, Seeds: map[string]int64{}}
+	for pkg, messages := range testOutput.pkgLevelMessagesByPackage {
+		for _, message := range messages {
+			if match := shuffleSeed.FindStringSubmatch(message); match != nil {
+				seed, _ := strconv.ParseInt(match[1], 10, 64)
+				run.Seeds[pkg] = seed
+			}
+		}
+	}
+
+	for _, line := range testOutput.testLines {
+		if line.Action != statPass && line.Action != statFail {
+			continue
+		}
+		test := outcomes.test(line.Package, line.Test)
+		if line.Action == statPass {
+			test.Passed++
+			continue
+		}
+		test.Failed++
+		run.Failed++
+		failure := flakyFailure{Seed: run.Seeds[line.Package], Count: count}
+		if !slices.ContainsFunc(test.Failures, func(f flakyFailure) bool { return f == failure }) {
+			test.Failures = append(test.Failures, failure)
+		}
+	}
+	return run
+}
+
+func (outcomes *testOutcomes) test(pkg string, name string) *flakyTest {
+	for _, test := range *outcomes {
+		if test.Package == pkg && test.Name == name {
+			return test
+		}
+	}
+	test := &flakyTest{Name: name, Package: pkg}
+	*outcomes = append(*outcomes, test)
+	return test
+}
+
+// flaky returns the tests that passed and failed, with the commands that reproduce the failures.
+// The commands add the shuffle seed, the count and the package to the given command.
+// The tests of a single package are reproduced with the given package pattern.
+func (outcomes testOutcomes) flaky(command []string, pattern string) []flakyTest {
+	var packages []string
+	for _, test := range outcomes {
+		packages = append(packages, test.Package)
+	}
+	slices.Sort(packages)
+	singlePackage := len(slices.Compact(packages)) == 1
+
+	flaky := []flakyTest{}
+	for _, test := range outcomes {
+		if test.Passed == 0 || test.Failed == 0 {
+			continue
+		}
+		pkgPattern := test.Package
+		if singlePackage {
+			pkgPattern = pattern
+		}
+		for i, failure := range test.Failures {
+			args := append(slices.Clone(command), fmt.Sprintf("-shuffle=%d", failure.Seed), fmt.Sprintf("-count=%d", failure.Count), pkgPattern)
+			test.Failures[i].Command = shellJoin(args)
+		}
+		flaky = append(flaky, *test)
+	}
+	return flaky
+}
+
+// withoutShuffleFlags returns the testing flags without -shuffle and -count,
+// which are set for every run of the flaky check.
+func withoutShuffleFlags(flags []string) []string {
+	return slices.DeleteFunc(slices.Clone(flags), func(flag string) bool {
+		return strings.HasPrefix(flag, "-shuffle") || strings.HasPrefix(flag, "-count")
+	})
+}
+
+// reproduceCommand returns the command line that runs the tests like the flaky check,
+// without the shuffle seed, the count and the package pattern. The overlay file is left
+// out, it is removed when the check is done.
+func reproduceCommand(cfg ExerciseConfig) []string {
+	var command []string
+	if len(cfg.GoExperiment) > 0 {
+		command = append(command, "GOEXPERIMENT="+strings.Join(cfg.GoExperiment, ","))
+	}
+	command = append(command, "go", "test")
+	cfg.overlayFile = ""
+	command = append(command, buildFlags(cfg)...)
+	return append(command, withoutShuffleFlags(cfg.TestingFlags)...)
+}
+
+// shellJoin joins the arguments of a command line for a POSIX shell,
+// arguments with special characters are quoted in single quotes.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=+.,/:@%") == "" {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,95 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestOutcomes(t *testing.T) {
+	var outcomes testOutcomes
+	runs := []flakyRun{
+		outcomes.add(&parsedTestOutput{
+			pkgLevelMessagesByPackage: map[string][]string{"leap": {"-test.shuffle 42\n"}},
+			testLines: []testLine{
+				{Action: "run", Package: "leap", Test: "TestLeap"},
+				{Action: "pass", Package: "leap", Test: "TestLeap"},
+				{Action: "pass", Package: "leap", Test: "TestStable"},
+			},
+		}, 1),
+		outcomes.add(&parsedTestOutput{
+			pkgLevelMessagesByPackage: map[string][]string{"leap": {"-test.shuffle 7\n", "FAIL\n"}},
+			testLines: []testLine{
+				{Action: "fail", Package: "leap", Test: "TestLeap"},
+				{Action: "fail", Package: "leap", Test: "TestLeap"},
+				{Action: "pass", Package: "leap", Test: "TestStable"},
+				{Action: "skip", Package: "leap", Test: "TestSkipped"},
+			},
+		}, 2),
+	}
+
+	assert.Equal(t, []flakyRun{
+		{Count: 1, Seeds: map[string]int64{"leap": 42}},
+		{Count: 2, Seeds: map[string]int64{"leap": 7}, Failed: 2},
+	}, runs)
+	assert.Equal(t, []flakyTest{
+		{
+			Name:     "TestLeap",
+			Package:  "leap",
+			Passed:   1,
+			Failed:   2,
+			Failures: []flakyFailure{{Seed: 7, Count: 2, Command: "go test -shuffle=7 -count=2 ."}},
+		},
+	}, outcomes.flaky([]string{"go", "test"}, "."))
+}
+
+func TestReproduceCommand(t *testing.T) {
+	cfg := ExerciseConfig{
+		BuildTags:    []string{"integration"},
+		GoExperiment: []string{"rangefunc"},
+		TestingFlags: []string{"-run=Test Leap", "-shuffle=on", "-count=3", "-race"},
+		overlayFile:  "/tmp/overlay.json",
+	}
+	var outcomes testOutcomes
+	outcomes.add(&parsedTestOutput{testLines: []testLine{{Action: "pass", Package: "leap", Test: "TestLeap"}}}, 1)
+	outcomes.add(&parsedTestOutput{
+		pkgLevelMessagesByPackage: map[string][]string{"leap": {"-test.shuffle 7\n"}},
+		testLines:                 []testLine{{Action: "fail", Package: "leap", Test: "TestLeap"}},
+	}, 2)
+
+	flaky := outcomes.flaky(reproduceCommand(cfg), ".")
+	require.Len(t, flaky, 1)
+	assert.Equal(t, "GOEXPERIMENT=rangefunc go test -tags=integration '-run=Test Leap' -race -shuffle=7 -count=2 .",
+		flaky[0].Failures[0].Command)
+}
+
+func TestCheckFlakiness(t *testing.T) {
+	var report flakyReport
+	output := CheckFlakiness(filepath.Join("testdata", "practice", "flaky"), Options{}, 3)
+	require.NoError(t, json.Unmarshal(output, &report))
+
+	assert.Equal(t, statFail, report.Status)
+	require.Len(t, report.Runs, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{report.Runs[0].Count, report.Runs[1].Count, report.Runs[2].Count})
+	require.Len(t, report.FlakyTests, 1, "only the test depending on global state is flaky")
+	flaky := report.FlakyTests[0]
+	assert.Equal(t, "TestAdd", flaky.Name)
+	assert.Equal(t, 3, flaky.Passed)
+	assert.Equal(t, 3, flaky.Failed)
+	require.Len(t, flaky.Failures, 2)
+	assert.Equal(t, report.Runs[1].Seeds["counter"], flaky.Failures[0].Seed)
+	assert.Equal(t, 2, flaky.Failures[0].Count)
+}
+
+func TestCheckFlakiness_BuildFailure(t *testing.T) {
+	var report flakyReport
+	output := CheckFlakiness(filepath.Join("testdata", "practice", "broken"), Options{}, 2)
+	require.NoError(t, json.Unmarshal(output, &report))
+
+	assert.Equal(t, statErr, report.Status)
+	assert.Contains(t, report.Message, "broken.go:")
+	assert.Empty(t, report.Runs)
+}
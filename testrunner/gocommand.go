@@ -0,0 +1,120 @@
+package testrunner
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// passedEnvVars are the environment variables of the test runner that are passed on
+// to the go command. Everything else, e.g. GOFLAGS, is not inherited so that the
+// behavior of the go command only depends on the exercise config.
+var passedEnvVars = []string{
+	"PATH", "HOME", "TMPDIR",
+	"GOROOT", "GOPATH", "GOCACHE", "GOMODCACHE", "GOENV",
+	"GOPROXY", "GONOPROXY", "GOSUMDB", "GONOSUMDB", "GOPRIVATE", "GOINSECURE",
+	"GOTOOLCHAIN", "GOTELEMETRY",
+	"CGO_ENABLED", "CC", "CXX",
+}
+
+// goExperiment matches a single GOEXPERIMENT value, e.g. "rangefunc" or "noaliases".
+var goExperiment = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// buildCommands are the subcommands of the go tool that accept the build flags.
+var buildCommands = []string{"build", "list", "test", "vet"}
+
+// goCommand creates the command for running the go tool with the given arguments
+// in the solution directory. The build flags of the exercise are added after the
+// subcommand and only a vetted set of environment variables is passed on.
+func goCommand(input_dir string, cfg ExerciseConfig, args ...string) *exec.Cmd {
+	goExe, err := exec.LookPath("go")
+	if err != nil {
+		log.Fatal("failed to find go executable: ", err)
+	}
+
+	cmdArgs := []string{goExe}
+	if len(args) > 0 {
+		cmdArgs = append(cmdArgs, args[0])
+		if slices.Contains(buildCommands, args[0]) {
+			cmdArgs = append(cmdArgs, buildFlags(cfg)...)
+		}
+		cmdArgs = append(cmdArgs, args[1:]...)
+	}
+
+	return &exec.Cmd{
+		Dir:  input_dir,
+		Path: goExe,
+		Args: cmdArgs,
+		Env:  goEnv(cfg),
+	}
+}
+
+// buildFlags returns the build tags of the exercise and the overlay of the solution files.
+func buildFlags(cfg ExerciseConfig) []string {
+	var flags []string
+	if len(cfg.BuildTags) > 0 {
+		flags = append(flags, "-tags="+strings.Join(cfg.BuildTags, ","))
+	}
+	if cfg.overlayFile != "" {
+		flags = append(flags, "-overlay="+cfg.overlayFile)
+	}
+	return flags
+}
+
+// goEnv returns the environment for the go command.
+func goEnv(cfg ExerciseConfig) []string {
+	var env []string
+	for _, name := range passedEnvVars {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	if len(cfg.GoExperiment) > 0 {
+		env = append(env, "GOEXPERIMENT="+strings.Join(cfg.GoExperiment, ","))
+	}
+	if cfg.localToolchain {
+		env = append(env, "GOTOOLCHAIN=local")
+	}
+	return env
+}
+
+// commandLine returns the command line shown in the report. The overlay is left out,
+// it only exists while the tests run.
+func commandLine(cmd *exec.Cmd) string {
+	args := slices.DeleteFunc(slices.Clone(cmd.Args), func(arg string) bool {
+		return strings.HasPrefix(arg, "-overlay=")
+	})
+	return strings.Join(args, " ")
+}
+
+// validateBuildConfig checks the build tags and GOEXPERIMENT values of the exercise config.
+// Build tags passed via the -tags testing flag are moved to the build tags, so they
+// are used for every invocation of the go command.
+func validateBuildConfig(cfg *ExerciseConfig) {
+	cfg.TestingFlags = slices.DeleteFunc(cfg.TestingFlags, func(flag string) bool {
+		tags, ok := strings.CutPrefix(flag, "-tags=")
+		if ok {
+			cfg.BuildTags = append(cfg.BuildTags, strings.Split(tags, ",")...)
+		}
+		return ok
+	})
+
+	cfg.BuildTags = slices.DeleteFunc(cfg.BuildTags, func(tag string) bool {
+		return !validConfigValue(cfg, "build tag", tag, buildTag)
+	})
+	cfg.GoExperiment = slices.DeleteFunc(cfg.GoExperiment, func(experiment string) bool {
+		return !validConfigValue(cfg, "GOEXPERIMENT value", experiment, goExperiment)
+	})
+}
+
+func validConfigValue(cfg *ExerciseConfig, kind string, value string, pattern *regexp.Regexp) bool {
+	if pattern.MatchString(value) {
+		return true
+	}
+	cfg.addConfigWarning(fmt.Sprintf("%s %q was ignored: it must match %s", kind, value, pattern))
+	return false
+}
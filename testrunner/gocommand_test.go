@@ -0,0 +1,53 @@
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBuildConfig(t *testing.T) {
+	cfg := ExerciseConfig{
+		TestingFlags: []string{"-race", "-tags=integration,go1.22"},
+		BuildTags:    []string{"exercism", "bad tag"},
+		GoExperiment: []string{"rangefunc", "aliases=1"},
+	}
+
+	validateBuildConfig(&cfg)
+
+	assert.Equal(t, []string{"-race"}, cfg.TestingFlags)
+	assert.Equal(t, []string{"exercism", "integration", "go1.22"}, cfg.BuildTags)
+	assert.Equal(t, []string{"rangefunc"}, cfg.GoExperiment)
+	assert.Equal(t, []testWarning{
+		{Kind: warnConfig, Message: `build tag "bad tag" was ignored: it must match ^[A-Za-z0-9_.]+$`},
+		{Kind: warnConfig, Message: `GOEXPERIMENT value "aliases=1" was ignored: it must match ^[a-z0-9]+$`},
+	}, cfg.warnings)
+}
+
+func TestGoCommand(t *testing.T) {
+	t.Setenv("GOFLAGS", "-v")
+	t.Setenv("GOCACHE", "/tmp/cache")
+
+	cmd := goCommand("solution", ExerciseConfig{
+		BuildTags:    []string{"exercism", "integration"},
+		GoExperiment: []string{"rangefunc"},
+	}, "test", "--json", ".")
+
+	assert.Equal(t, "solution", cmd.Dir)
+	assert.Equal(t, []string{"test", "-tags=exercism,integration", "--json", "."}, cmd.Args[1:])
+	assert.Contains(t, cmd.Env, "GOCACHE=/tmp/cache")
+	assert.Contains(t, cmd.Env, "GOEXPERIMENT=rangefunc")
+	assert.NotContains(t, cmd.Env, "GOFLAGS=-v")
+}
+
+func TestGoCommand_BuildFlags(t *testing.T) {
+	cfg := ExerciseConfig{BuildTags: []string{"exercism"}, overlayFile: "/tmp/overlay.json", localToolchain: true}
+
+	testCmd := goCommand("solution", cfg, "test", "--short", ".")
+	envCmd := goCommand("solution", cfg, "env", "GOVERSION")
+
+	assert.Equal(t, []string{"test", "-tags=exercism", "-overlay=/tmp/overlay.json", "--short", "."}, testCmd.Args[1:])
+	assert.Equal(t, []string{"env", "GOVERSION"}, envCmd.Args[1:])
+	assert.Contains(t, testCmd.Env, "GOTOOLCHAIN=local")
+	assert.Equal(t, testCmd.Args[0]+" test -tags=exercism --short .", commandLine(testCmd))
+}
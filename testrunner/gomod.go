@@ -0,0 +1,236 @@
+package testrunner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	goversion "go/version"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// addGoModRepair adds the repaired go.mod file of the solution to the overlay
+// and notes the changes in the warnings of the report.
+func addGoModRepair(input_dir string, cfg *ExerciseConfig, o *overlay) {
+	content, notes := repairGoMod(input_dir, *cfg)
+	if content == nil {
+		return
+	}
+	if err := o.addFile(input_dir, "go.mod", content); err != nil {
+		log.Printf("warning: go.mod was not repaired: %s", err)
+		return
+	}
+	// The go command reads the toolchain directive before it applies the overlay.
+	cfg.localToolchain = true
+	for _, note := range notes {
+		cfg.warnings = append(cfg.warnings, testWarning{Kind: warnGoMod, Message: note})
+	}
+}
+
+// addGoSumRepair completes the go.sum file of the solution with the checksums of the required
+// modules in the module cache. The go command refuses to build a solution with missing checksums
+// and adding them with 'go mod tidy' would modify the solution directory, so the checksums are
+// collected in a scratch module and the completed go.sum file is added to the overlay.
+func addGoSumRepair(input_dir string, cfg *ExerciseConfig, o *overlay) {
+	goModContent, err := o.content(input_dir, "go.mod")
+	if err != nil {
+		return
+	}
+	goMod, err := modfile.Parse("go.mod", goModContent, nil)
+	if err != nil || !slices.ContainsFunc(goMod.Require, func(req *modfile.Require) bool {
+		_, ok := replacedModule(goMod, req.Mod)
+		return ok
+	}) {
+		return
+	}
+	goSum, err := o.content(input_dir, "go.sum")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Printf("warning: go.sum could not be read: %s", err)
+		return
+	}
+
+	scratch, err := os.MkdirTemp("", "go-test-runner-gosum-")
+	if err != nil {
+		log.Printf("warning: go.sum was not completed: %s", err)
+		return
+	}
+	defer os.RemoveAll(scratch)
+	scratchGoMod, err := scratchGoMod(goMod)
+	if err != nil {
+		log.Printf("warning: go.sum was not completed: %s", err)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(scratch, "go.mod"), scratchGoMod, 0644); err != nil {
+		log.Printf("warning: go.sum was not completed: %s", err)
+		return
+	}
+	if goSum != nil {
+		if err := os.WriteFile(filepath.Join(scratch, "go.sum"), goSum, 0644); err != nil {
+			log.Printf("warning: go.sum was not completed: %s", err)
+			return
+		}
+	}
+	cmd := goCommand(scratch, *cfg, "mod", "download", "all")
+	// The checksums are only taken from the module cache, the tests run without network access.
+	cmd.Env = append(cmd.Env, "GOPROXY=off", "GOTOOLCHAIN=local")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		// The go command reports the modules that are not available when the tests run.
+		log.Printf("warning: go.sum was not completed: %s %s", err, output)
+		return
+	}
+	completed, err := os.ReadFile(filepath.Join(scratch, "go.sum"))
+	if err != nil || bytes.Equal(completed, goSum) {
+		return
+	}
+
+	if err := o.addFile(input_dir, "go.sum", completed); err != nil {
+		log.Printf("warning: go.sum was not completed: %s", err)
+		return
+	}
+	note := "The go.sum file of the solution is missing checksums, the tests ran with a completed one."
+	if goSum == nil {
+		note = "The solution does not have a go.sum file, the tests ran with a generated one."
+	}
+	cfg.warnings = append(cfg.warnings, testWarning{Kind: warnGoMod, Message: note})
+}
+
+// scratchGoMod returns the go.mod file for the scratch module of addGoSumRepair.
+// Directories of replace directives are relative to the solution directory and do not
+// exist next to the scratch module, so the modules replaced by a directory are dropped.
+// Their own requirements are not completed, the go command reports them when the tests run.
+func scratchGoMod(goMod *modfile.File) ([]byte, error) {
+	var local []string
+	for _, req := range goMod.Require {
+		if _, ok := replacedModule(goMod, req.Mod); !ok {
+			local = append(local, req.Mod.Path)
+		}
+	}
+	for _, path := range local {
+		if err := goMod.DropRequire(path); err != nil {
+			return nil, err
+		}
+	}
+	for _, replace := range slices.Clone(goMod.Replace) {
+		if replace.New.Version == "" {
+			if err := goMod.DropReplace(replace.Old.Path, replace.Old.Version); err != nil {
+				return nil, err
+			}
+		}
+	}
+	goMod.Cleanup()
+	return goMod.Format()
+}
+
+// repairGoMod returns a go.mod file to use instead of the one of the solution, nil if the
+// go.mod file of the solution can be used as is. It also returns notes about the changes.
+// A go.mod file is generated if the solution does not have one, and the go and toolchain
+// directives are lowered if they require a newer Go version than the installed one,
+// which would make the go command try to download a toolchain.
+func repairGoMod(input_dir string, cfg ExerciseConfig) ([]byte, []string) {
+	goModPath := filepath.Join(input_dir, "go.mod")
+	content, err := os.ReadFile(goModPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return generatedGoMod(input_dir)
+	}
+	if err != nil {
+		log.Printf("warning: go.mod could not be read: %s", err)
+		return nil, nil
+	}
+
+	goMod, err := modfile.Parse(goModPath, content, nil)
+	if err != nil || goMod.Go == nil {
+		// The go command reports the problems of the go.mod file.
+		return nil, nil
+	}
+	installed := installedGoVersion(cfg)
+	if installed == "" {
+		return nil, nil
+	}
+
+	var notes []string
+	if goversion.Compare("go"+goMod.Go.Version, installed) > 0 {
+		notes = append(notes, fmt.Sprintf("The go.mod file requires Go %s, the tests ran with Go %s instead.",
+			goMod.Go.Version, strings.TrimPrefix(installed, "go")))
+		if err := goMod.AddGoStmt(strings.TrimPrefix(installed, "go")); err != nil {
+			log.Printf("warning: failed to change the go version of go.mod: %s", err)
+			return nil, nil
+		}
+	}
+	if goMod.Toolchain != nil && goversion.Compare(goMod.Toolchain.Name, installed) > 0 {
+		notes = append(notes, fmt.Sprintf("The toolchain %s of the go.mod file is not available, the tests ran with %s instead.",
+			goMod.Toolchain.Name, installed))
+		goMod.DropToolchainStmt()
+	}
+	if len(notes) == 0 {
+		return nil, nil
+	}
+
+	repaired, err := goMod.Format()
+	if err != nil {
+		log.Printf("warning: failed to format the adjusted go.mod: %s", err)
+		return nil, nil
+	}
+	return repaired, notes
+}
+
+// generatedGoMod returns a go.mod file for a solution without one. The module is named
+// after the package of the solution and uses the Go version of the go.mod files of the exercises.
+func generatedGoMod(input_dir string) ([]byte, []string) {
+	moduleName := "solution"
+	if files := parseGoFiles(token.NewFileSet(), input_dir, false); len(files) > 0 {
+		moduleName = files[0].Name.Name
+	}
+	goMod := &modfile.File{}
+	if err := goMod.AddModuleStmt(moduleName); err != nil {
+		log.Printf("warning: failed to generate go.mod: %s", err)
+		return nil, nil
+	}
+	if err := goMod.AddGoStmt(exerciseGoVersion()); err != nil {
+		log.Printf("warning: failed to generate go.mod: %s", err)
+		return nil, nil
+	}
+	content, err := goMod.Format()
+	if err != nil {
+		log.Printf("warning: failed to generate go.mod: %s", err)
+		return nil, nil
+	}
+	return content, []string{"The solution does not have a go.mod file, the tests ran with a generated one."}
+}
+
+// exerciseGoVersion returns the Go version of the go.mod files given to the students,
+// which is also used for the external packages.
+func exerciseGoVersion() string {
+	var manifest struct {
+		Go string `json:"go"`
+	}
+	if err := json.Unmarshal(supportedModulesManifest, &manifest); err != nil {
+		log.Fatalf("failed to parse the supported modules: %s", err)
+	}
+	return manifest.Go
+}
+
+// installedGoVersion returns the version of the installed go command, e.g. "go1.26.1",
+// or an empty string if it is unknown.
+func installedGoVersion(cfg ExerciseConfig) string {
+	cmd := goCommand(os.TempDir(), cfg, "env", "GOVERSION")
+	// The go.mod file of the solution must not make the go command switch the toolchain.
+	cmd.Env = append(cmd.Env, "GOTOOLCHAIN=local")
+	output, err := cmd.Output()
+	if err != nil {
+		log.Printf("warning: failed to determine the go version: %s", err)
+		return ""
+	}
+	goVersion := strings.TrimSpace(string(output))
+	if !goversion.IsValid(goVersion) {
+		return ""
+	}
+	return goVersion
+}
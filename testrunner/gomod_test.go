@@ -0,0 +1,150 @@
+package testrunner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/mod/modfile"
+)
+
+func TestRepairGoMod(t *testing.T) {
+	installed := installedGoVersion(ExerciseConfig{})
+	require.NotEmpty(t, installed)
+	installedVersion := strings.TrimPrefix(installed, "go")
+
+	tests := []struct {
+		name          string
+		goMod         string
+		expectedGoMod string
+		expectedNotes []string
+	}{
+		{
+			name:  "supported go version",
+			goMod: "module leap\n\ngo 1.26\n",
+		},
+		{
+			name:          "newer go version",
+			goMod:         "module leap\n\ngo 1.99\n",
+			expectedGoMod: "module leap\n\ngo " + installedVersion + "\n",
+			expectedNotes: []string{"The go.mod file requires Go 1.99, the tests ran with Go " + installedVersion + " instead."},
+		},
+		{
+			name:          "newer toolchain",
+			goMod:         "module leap\n\ngo 1.26\n\ntoolchain go1.99.1\n",
+			expectedGoMod: "module leap\n\ngo 1.26\n",
+			expectedNotes: []string{"The toolchain go1.99.1 of the go.mod file is not available, the tests ran with " + installed + " instead."},
+		},
+		{
+			name:  "invalid go.mod",
+			goMod: "module leap\n\ngo version 1.99\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input_dir := t.TempDir()
+			writeFile(t, filepath.Join(input_dir, "go.mod"), tt.goMod)
+
+			goMod, notes := repairGoMod(input_dir, ExerciseConfig{})
+
+			assert.Equal(t, tt.expectedGoMod, string(goMod))
+			assert.Equal(t, tt.expectedNotes, notes)
+		})
+	}
+}
+
+func TestRepairGoMod_Missing(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "leap.go"), "package leap\n")
+
+	goMod, notes := repairGoMod(input_dir, ExerciseConfig{})
+
+	assert.Equal(t, "module leap\n\ngo "+exerciseGoVersion()+"\n", string(goMod))
+	assert.Equal(t, []string{"The solution does not have a go.mod file, the tests ran with a generated one."}, notes)
+}
+
+func TestAddGoModRepair(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "leap.go"), "package leap\n")
+	o := &overlay{}
+	defer o.remove()
+	cfg := ExerciseConfig{}
+
+	addGoModRepair(input_dir, &cfg, o)
+
+	assert.Len(t, o.Replace, 1)
+	assert.True(t, cfg.localToolchain)
+	assert.Equal(t, []testWarning{
+		{Kind: warnGoMod, Message: "The solution does not have a go.mod file, the tests ran with a generated one."},
+	}, cfg.warnings)
+}
+
+// cachedTestify returns the requirement of the test runner on testify,
+// its module is in the module cache because the test runner depends on it.
+func cachedTestify(t *testing.T) *modfile.Require {
+	t.Helper()
+	runnerGoMod, err := os.ReadFile(filepath.Join("..", "go.mod"))
+	require.NoError(t, err)
+	goMod, err := modfile.Parse("go.mod", runnerGoMod, nil)
+	require.NoError(t, err)
+	testify := providingModule(goMod.Require, "github.com/stretchr/testify/assert")
+	require.NotNil(t, testify)
+	return testify
+}
+
+func TestAddGoSumRepair(t *testing.T) {
+	testify := cachedTestify(t)
+
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "go.mod"), "module leap\n\ngo 1.26\n\nrequire "+testify.Mod.Path+" "+testify.Mod.Version+"\n")
+	o := &overlay{}
+	defer o.remove()
+	cfg := ExerciseConfig{}
+
+	addGoSumRepair(input_dir, &cfg, o)
+
+	goSum, err := o.content(input_dir, "go.sum")
+	require.NoError(t, err)
+	assert.Contains(t, string(goSum), testify.Mod.Path+" "+testify.Mod.Version+" h1:")
+	assert.NoFileExists(t, filepath.Join(input_dir, "go.sum"))
+	assert.Equal(t, []testWarning{
+		{Kind: warnGoMod, Message: "The solution does not have a go.sum file, the tests ran with a generated one."},
+	}, cfg.warnings)
+}
+
+func TestAddGoSumRepair_DirectoryReplacement(t *testing.T) {
+	testify := cachedTestify(t)
+
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "go.mod"), "module leap\n\ngo 1.26\n\nrequire (\n"+
+		"\t"+testify.Mod.Path+" "+testify.Mod.Version+"\n"+
+		"\texample.com/helpers v1.0.0\n"+
+		")\n\nreplace example.com/helpers => ./helpers\n")
+	writeFile(t, filepath.Join(input_dir, "helpers", "go.mod"), "module example.com/helpers\n\ngo 1.26\n")
+	o := &overlay{}
+	defer o.remove()
+	cfg := ExerciseConfig{}
+
+	addGoSumRepair(input_dir, &cfg, o)
+
+	goSum, err := o.content(input_dir, "go.sum")
+	require.NoError(t, err)
+	assert.Contains(t, string(goSum), testify.Mod.Path+" "+testify.Mod.Version+" h1:")
+	assert.NotContains(t, string(goSum), "example.com/helpers")
+	assert.Len(t, cfg.warnings, 1)
+}
+
+func TestAddGoSumRepair_NoRequirements(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "go.mod"), "module leap\n\ngo 1.26\n")
+	o := &overlay{}
+	cfg := ExerciseConfig{}
+
+	addGoSumRepair(input_dir, &cfg, o)
+
+	assert.Empty(t, o.Replace)
+	assert.Empty(t, cfg.warnings)
+}
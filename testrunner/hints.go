@@ -0,0 +1,250 @@
+package testrunner
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// undefinedInTest matches compile errors about identifiers the tests use but the solution
+// does not declare, e.g. "./leap_test.go:12:6: undefined: IsLeapYear". Tests in an
+// external test package refer to them with the package name, e.g. "undefined: leap.IsLeapYear".
+var undefinedInTest = regexp.MustCompile(`(?m)^\S+_test\.go:\d+:\d+: undefined: (?:(\w+)\.)?(\w+)$`)
+
+// declaration is a top level declaration of the solution.
+type declaration struct {
+	name string
+	// receiver is the type name of a method, empty for everything else.
+	receiver string
+	// params is the number of parameters of a function or method, -1 for everything else.
+	params int
+}
+
+// missingDeclarationHints returns a hint for every identifier the tests expect but that
+// the solution does not declare, pointing out the declaration the student most likely meant.
+// Identifiers without a similar declaration get no hint.
+func missingDeclarationHints(buildOutput string, input_dir string) []string {
+	matches := undefinedInTest.FindAllStringSubmatch(buildOutput, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	pkgName, decls := solutionDeclarations(input_dir)
+	calls := testCallArguments(input_dir)
+
+	var hints []string
+	var seen []string
+	for _, match := range matches {
+		qualifier, name := match[1], match[2]
+		if qualifier != "" && qualifier != pkgName || slices.Contains(seen, name) {
+			continue
+		}
+		seen = append(seen, name)
+		args, ok := calls[name]
+		if !ok {
+			args = -1
+		}
+		if hint := declarationHint(name, args, decls); hint != "" {
+			hints = append(hints, hint)
+		}
+	}
+	return hints
+}
+
+// declarationHint describes the declaration that is closest to the missing identifier name.
+// args is the number of arguments the tests call it with, -1 if it is not called.
+// It returns an empty hint if no declaration is similar, the compiler error says it all.
+func declarationHint(name string, args int, decls []declaration) string {
+	for _, decl := range decls {
+		if decl.name == name && decl.receiver != "" {
+			return fmt.Sprintf("`%s` is not declared in the solution, the tests expect a function but you declared a method of `%s`.",
+				name, decl.receiver)
+		}
+	}
+
+	type candidate struct {
+		declaration
+		distance int
+		mismatch bool
+	}
+	var candidates []candidate
+	for _, decl := range decls {
+		if decl.receiver != "" || args >= 0 && decl.params < 0 {
+			// Constants, variables and types are no candidates for a function the tests call.
+			continue
+		}
+		distance, ok := nameDistance(name, decl.name)
+		if !ok {
+			continue
+		}
+		mismatch := args >= 0 && decl.params >= 0 && args != decl.params
+		candidates = append(candidates, candidate{decl, distance, mismatch})
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	best := slices.MinFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(
+			cmp.Compare(a.distance, b.distance),
+			compareBool(a.mismatch, b.mismatch),
+			cmp.Compare(a.name, b.name),
+		)
+	})
+	hint := fmt.Sprintf("`%s` is not declared in the solution, did you mean `%s` (you declared `%s`", name, name, best.name)
+	if best.mismatch {
+		hint += fmt.Sprintf(" with %s, the tests pass %s", plural(best.params, "parameter"), plural(args, "argument"))
+	}
+	return hint + ")?"
+}
+
+// nameDistance returns the edit distance between the names ignoring case, and whether
+// they are similar enough to suggest one for the other. Names that only differ in case
+// have the distance 0, names that contain the other one are similar unless the contained
+// name is less than half as long.
+func nameDistance(expected string, declared string) (int, bool) {
+	a, b := strings.ToLower(expected), strings.ToLower(declared)
+	distance := editDistance(a, b)
+	shorter := min(len(a), len(b))
+	contained := shorter >= 3 && 2*shorter >= max(len(a), len(b)) && (strings.Contains(a, b) || strings.Contains(b, a))
+	return distance, contained || distance <= max(2, len(a)/3)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	s, t := []rune(a), []rune(b)
+	row := make([]int, len(t)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(t); j++ {
+			substitution := diagonal
+			if s[i-1] != t[j-1] {
+				substitution++
+			}
+			diagonal = row[j]
+			row[j] = min(row[j]+1, row[j-1]+1, substitution)
+		}
+	}
+	return row[len(t)]
+}
+
+// solutionDeclarations returns the package name and the top level declarations of the
+// non-test files of the solution.
+func solutionDeclarations(input_dir string) (string, []declaration) {
+	var pkgName string
+	var decls []declaration
+	for _, file := range parseGoFiles(token.NewFileSet(), input_dir, false) {
+		pkgName = file.Name.Name
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				d := declaration{name: decl.Name.Name, params: decl.Type.Params.NumFields()}
+				if decl.Recv != nil && len(decl.Recv.List) > 0 {
+					d.receiver = receiverName(decl.Recv.List[0].Type)
+				}
+				decls = append(decls, d)
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						decls = append(decls, declaration{name: spec.Name.Name, params: -1})
+					case *ast.ValueSpec:
+						for _, ident := range spec.Names {
+							decls = append(decls, declaration{name: ident.Name, params: -1})
+						}
+					}
+				}
+			}
+		}
+	}
+	return pkgName, slices.DeleteFunc(decls, func(d declaration) bool {
+		return d.name == "_" || d.name == "init" || d.name == "main"
+	})
+}
+
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.IndexListExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// testCallArguments returns the number of arguments of the calls in the test files,
+// keyed by the name of the called function. Calls of a package, e.g. leap.IsLeapYear(1996),
+// are included under the name of the function.
+func testCallArguments(input_dir string) map[string]int {
+	args := map[string]int{}
+	for _, file := range parseGoFiles(token.NewFileSet(), input_dir, true) {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				args[fun.Name] = len(call.Args)
+			case *ast.SelectorExpr:
+				args[fun.Sel.Name] = len(call.Args)
+			}
+			return true
+		})
+	}
+	return args
+}
+
+// parseGoFiles parses either the test files or the other go files in input_dir.
+// Files that cannot be parsed are skipped.
+func parseGoFiles(fset *token.FileSet, input_dir string, testFiles bool) []*ast.File {
+	paths, err := filepath.Glob(filepath.Join(input_dir, "*.go"))
+	if err != nil {
+		log.Printf("warning: input_dir '%s' cannot be read: %s", input_dir, err)
+		return nil
+	}
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") != testFiles {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			log.Printf("warning: failed to parse %s: %s", path, err)
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+func compareBool(a bool, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,82 @@
+package testrunner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeclarationHint(t *testing.T) {
+	decls := []declaration{
+		{name: "IsLeap", params: 1},
+		{name: "isleapyear", params: -1},
+		{name: "Add", params: 2},
+		{name: "Distance", receiver: "Point", params: 1},
+		{name: "Gigasecond", params: -1},
+	}
+	tests := []struct {
+		name     string
+		args     int
+		expected string
+	}{
+		{
+			name:     "IsLeapYear",
+			args:     1,
+			expected: "`IsLeapYear` is not declared in the solution, did you mean `IsLeapYear` (you declared `IsLeap`)?",
+		},
+		{
+			name:     "IsLeapYear",
+			args:     -1,
+			expected: "`IsLeapYear` is not declared in the solution, did you mean `IsLeapYear` (you declared `isleapyear`)?",
+		},
+		{
+			name:     "AddAll",
+			args:     3,
+			expected: "`AddAll` is not declared in the solution, did you mean `AddAll` (you declared `Add` with 2 parameters, the tests pass 3 arguments)?",
+		},
+		{
+			name:     "Distance",
+			args:     2,
+			expected: "`Distance` is not declared in the solution, the tests expect a function but you declared a method of `Point`.",
+		},
+		{
+			// Add takes 2 parameters like the call, but it is less than half as long as the name.
+			name:     "AddGigaseconds",
+			args:     2,
+			expected: "",
+		},
+		{
+			name:     "AddGigasecond",
+			args:     1,
+			expected: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, declarationHint(tt.name, tt.args, decls))
+		})
+	}
+}
+
+func TestMissingDeclarationHints(t *testing.T) {
+	buildOutput := "# leap [leap.test]\n" +
+		"./leap_test.go:18:17: undefined: IsLeapYear\n" +
+		"./leap_test.go:19:17: undefined: IsLeapYear\n" +
+		"./leap_test.go:26:15: undefined: DaysInYear\n" +
+		"./leap.go:4:2: undefined: unknownVar\n"
+
+	hints := missingDeclarationHints(buildOutput, filepath.Join("testdata", "practice", "misnamed_func"))
+
+	assert.Equal(t, []string{
+		"`IsLeapYear` is not declared in the solution, did you mean `IsLeapYear` (you declared `IsLeap`)?",
+		"`DaysInYear` is not declared in the solution, the tests expect a function but you declared a method of `Calendar`.",
+	}, hints)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("leap", "leap"))
+	assert.Equal(t, 4, editDistance("isleapyear", "isleap"))
+	assert.Equal(t, 1, editDistance("addgigasecond", "addgigasecnd"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
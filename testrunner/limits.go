@@ -0,0 +1,138 @@
+package testrunner
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LimitMemoryCommand is the subcommand of the test runner that runs the test binary
+// with a memory limit. It is passed to `go test -exec` so the limit only applies to
+// the test binary and not to the go command and the compiler.
+const LimitMemoryCommand = "exec-with-memory-limit"
+
+const (
+	minTimeout        = time.Second
+	maxTimeout        = 5 * time.Minute
+	minOutputBytes    = 1 << 10
+	maxOutputBytes    = 64 << 20
+	minMemoryBytes    = 128 << 20 // the Go runtime of the test binary fails to start with less
+	maxMemoryBytes    = 4 << 30
+	memoryLimitOSName = "linux"
+)
+
+// validateLimits checks the limits of the exercise config. Invalid limits are
+// removed and reported as warnings.
+// A timeout passed via the -timeout testing flag is used if there is no timeout in the config.
+func validateLimits(cfg *ExerciseConfig) {
+	cfg.TestingFlags = slices.DeleteFunc(cfg.TestingFlags, func(flag string) bool {
+		timeout, ok := strings.CutPrefix(flag, "-timeout=")
+		if ok && cfg.Timeout == "" {
+			cfg.Timeout = timeout
+		}
+		return ok
+	})
+
+	if cfg.Timeout != "" {
+		if err := durationInRange(minTimeout, maxTimeout)(cfg.Timeout); err != nil {
+			cfg.addLimitWarning("timeout", err)
+			cfg.Timeout = ""
+		}
+	}
+	if cfg.MaxOutputBytes != 0 {
+		if err := int64InRange(minOutputBytes, maxOutputBytes)(cfg.MaxOutputBytes); err != nil {
+			cfg.addLimitWarning("maxOutputBytes", err)
+			cfg.MaxOutputBytes = 0
+		}
+	}
+	if cfg.MaxMemoryBytes != 0 {
+		err := int64InRange(minMemoryBytes, maxMemoryBytes)(cfg.MaxMemoryBytes)
+		switch {
+		case err != nil:
+		case runtime.GOOS != memoryLimitOSName:
+			err = fmt.Errorf("memory limits are only supported on %s", memoryLimitOSName)
+		case slices.Contains(cfg.TestingFlags, "-race"):
+			// The race detector reserves more memory than any sensible limit at startup.
+			err = fmt.Errorf("memory limits cannot be used together with -race")
+		}
+		if err != nil {
+			cfg.addLimitWarning("maxMemoryBytes", err)
+			cfg.MaxMemoryBytes = 0
+		}
+	}
+}
+
+func (cfg *ExerciseConfig) addLimitWarning(key string, err error) {
+	cfg.addConfigWarning(fmt.Sprintf("%s was ignored: %s", key, err))
+}
+
+func int64InRange(minValue, maxValue int64) func(int64) error {
+	return func(value int64) error {
+		if value < minValue || value > maxValue {
+			return fmt.Errorf("%d must be between %d and %d", value, minValue, maxValue)
+		}
+		return nil
+	}
+}
+
+// limitTestFlags returns the flags for `go test` that enforce the limits of the exercise.
+func limitTestFlags(cfg ExerciseConfig) []string {
+	var flags []string
+	if cfg.Timeout != "" {
+		flags = append(flags, "-timeout="+cfg.Timeout)
+	}
+	if cfg.MaxMemoryBytes > 0 {
+		exe, err := os.Executable()
+		if err != nil {
+			log.Printf("warning: memory limit not applied, failed to find the test runner executable: %s", err)
+			return flags
+		}
+		execCmd, err := joinQuoted([]string{exe, LimitMemoryCommand, strconv.FormatInt(cfg.MaxMemoryBytes, 10)})
+		if err != nil {
+			log.Printf("warning: memory limit not applied: %s", err)
+			return flags
+		}
+		flags = append(flags, "-exec="+execCmd)
+	}
+	return flags
+}
+
+// joinQuoted joins the arguments of a command for flags like -exec that the go command
+// splits into fields. Like the go command, it quotes arguments with spaces or quotes
+// in single or double quotes, there are no escape sequences.
+func joinQuoted(args []string) (string, error) {
+	var quoted []string
+	for _, arg := range args {
+		switch {
+		case arg != "" && !strings.ContainsAny(arg, " \t\n\r'\""):
+			quoted = append(quoted, arg)
+		case !strings.Contains(arg, "'"):
+			quoted = append(quoted, "'"+arg+"'")
+		case !strings.Contains(arg, `"`):
+			quoted = append(quoted, `"`+arg+`"`)
+		default:
+			return "", fmt.Errorf("%s contains both single and double quotes", arg)
+		}
+	}
+	return strings.Join(quoted, " "), nil
+}
+
+// ExecWithMemoryLimit replaces the current process with the command given in args[1:]
+// after limiting the memory to args[0] bytes.
+func ExecWithMemoryLimit(args []string) {
+	if len(args) < 2 {
+		log.Fatalf("usage: go-test-runner %s max_bytes command [args...]", LimitMemoryCommand)
+	}
+	maxBytes, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		log.Fatalf("invalid memory limit %q: %s", args[0], err)
+	}
+	if err := execWithMemoryLimit(maxBytes, args[1:]); err != nil {
+		log.Fatalf("failed to run %s with memory limit: %s", args[1], err)
+	}
+}
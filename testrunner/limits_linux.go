@@ -0,0 +1,21 @@
+package testrunner
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// execWithMemoryLimit limits the data segment of the process, which includes
+// the heap of Go programs, and then replaces the process with the command.
+func execWithMemoryLimit(maxBytes int64, argv []string) error {
+	limit := &syscall.Rlimit{Cur: uint64(maxBytes), Max: uint64(maxBytes)}
+	if err := syscall.Setrlimit(syscall.RLIMIT_DATA, limit); err != nil {
+		return err
+	}
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(path, argv, os.Environ())
+}
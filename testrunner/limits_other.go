@@ -0,0 +1,12 @@
+//go:build !linux
+
+package testrunner
+
+import (
+	"errors"
+	"runtime"
+)
+
+func execWithMemoryLimit(maxBytes int64, argv []string) error {
+	return errors.New("memory limits are not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,108 @@
+package testrunner
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLimits(t *testing.T) {
+	tests := []struct {
+		name             string
+		cfg              ExerciseConfig
+		expected         ExerciseConfig
+		expectedWarnings []string
+	}{
+		{
+			name:     "valid limits",
+			cfg:      ExerciseConfig{Timeout: "30s", MaxOutputBytes: 1 << 20},
+			expected: ExerciseConfig{Timeout: "30s", MaxOutputBytes: 1 << 20},
+		},
+		{
+			name:     "timeout from testing flags",
+			cfg:      ExerciseConfig{TestingFlags: []string{"-timeout=10s", "-count=1"}},
+			expected: ExerciseConfig{TestingFlags: []string{"-count=1"}, Timeout: "10s"},
+		},
+		{
+			name:     "timeout in config takes precedence over testing flag",
+			cfg:      ExerciseConfig{TestingFlags: []string{"-timeout=10s"}, Timeout: "20s"},
+			expected: ExerciseConfig{TestingFlags: []string{}, Timeout: "20s"},
+		},
+		{
+			name:     "invalid limits",
+			cfg:      ExerciseConfig{Timeout: "1h", MaxOutputBytes: -1, MaxMemoryBytes: 1 << 20},
+			expected: ExerciseConfig{},
+			expectedWarnings: []string{
+				`timeout was ignored: "1h" must be a duration between 1s and 5m0s`,
+				"maxOutputBytes was ignored: -1 must be between 1024 and 67108864",
+				"maxMemoryBytes was ignored: 1048576 must be between 134217728 and 4294967296",
+			},
+		},
+		{
+			name:     "memory limit with race detector",
+			cfg:      ExerciseConfig{TestingFlags: []string{"-race"}, MaxMemoryBytes: 256 << 20},
+			expected: ExerciseConfig{TestingFlags: []string{"-race"}},
+			expectedWarnings: []string{
+				"maxMemoryBytes was ignored: memory limits cannot be used together with -race",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.cfg.MaxMemoryBytes != 0 && runtime.GOOS != memoryLimitOSName {
+				t.Skip("memory limits are not supported on " + runtime.GOOS)
+			}
+			validateLimits(&tt.cfg)
+			var messages []string
+			for _, warning := range tt.cfg.warnings {
+				messages = append(messages, warning.Message)
+			}
+			tt.cfg.warnings = nil
+			assert.Equal(t, tt.expected, tt.cfg)
+			assert.Equal(t, tt.expectedWarnings, messages)
+		})
+	}
+}
+
+func TestJoinQuoted(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "plain arguments",
+			args:     []string{"/usr/local/bin/test-runner", LimitMemoryCommand, "268435456"},
+			expected: "/usr/local/bin/test-runner exec-with-memory-limit 268435456",
+		},
+		{
+			name:     "space",
+			args:     []string{"/opt/test runner/bin", "x"},
+			expected: "'/opt/test runner/bin' x",
+		},
+		{
+			name:     "single quote",
+			args:     []string{"/opt/it's/bin"},
+			expected: `"/opt/it's/bin"`,
+		},
+		{
+			name:     "backslash is not escaped",
+			args:     []string{`C:\test runner\bin`},
+			expected: `'C:\test runner\bin'`,
+		},
+		{
+			name:    "both quotes",
+			args:    []string{`/opt/"it's"/bin`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joined, err := joinQuoted(tt.args)
+			assert.Equal(t, tt.expected, joined)
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package testrunner
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/build"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// verifyModules checks that the packages imported by the solution belong to the supported
+// modules and that the modules required by the solution are available in the module cache,
+// because the tests run without network access.
+// The go.mod file is read through the overlay, so the repaired one is checked.
+// It returns a description of every problem followed by the supported modules,
+// nothing if the solution has no go.mod file.
+func verifyModules(input_dir string, cfg ExerciseConfig, o *overlay) []string {
+	goModPath := filepath.Join(input_dir, "go.mod")
+	content, err := o.content(input_dir, "go.mod")
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("warning: modules not verified, go.mod could not be read: %s", err)
+		}
+		return nil
+	}
+	goMod, err := modfile.Parse(goModPath, content, nil)
+	if err != nil {
+		// The go command reports the problems of the go.mod file.
+		return nil
+	}
+	modCache := moduleCache()
+	supported := supportedModules()
+
+	var problems []string
+	unavailable := map[string]bool{}
+	for _, req := range goMod.Require {
+		mod, ok := replacedModule(goMod, req.Mod)
+		if ok && !moduleCached(modCache, mod, ".mod") {
+			unavailable[req.Mod.Path] = true
+			problems = append(problems, fmt.Sprintf("module %s %s is not supported on this track.", req.Mod.Path, req.Mod.Version))
+		}
+	}
+
+	ownModule := ""
+	if goMod.Module != nil {
+		ownModule = goMod.Module.Mod.Path
+	}
+	for _, importPath := range externalImports(input_dir, ownModule, cfg) {
+		req := providingModule(goMod.Require, importPath)
+		if req != nil && unavailable[req.Mod.Path] {
+			continue
+		}
+		idx := -1
+		if req != nil {
+			idx = slices.IndexFunc(supported, func(mod supportedModule) bool { return mod.Path == req.Mod.Path })
+		}
+		if idx < 0 {
+			problems = append(problems, fmt.Sprintf("package %s is not supported on this track.", importPath))
+			continue
+		}
+		mod, ok := replacedModule(goMod, req.Mod)
+		if ok && mod.Version != supported[idx].Version {
+			// Other versions may be in the module cache, e.g. as dependencies of the test runner,
+			// but only the supported version is downloaded for the solutions.
+			problems = append(problems, fmt.Sprintf("package %s is not supported on this track in version %s of module %s.",
+				importPath, mod.Version, mod.Path))
+			continue
+		}
+		if ok && moduleCached(modCache, mod, ".mod") && !moduleCached(modCache, mod, ".zip") {
+			problems = append(problems, fmt.Sprintf("package %s is not supported on this track, the source code of module %s %s is not available.",
+				importPath, req.Mod.Path, req.Mod.Version))
+		}
+	}
+	if len(problems) > 0 {
+		problems = append(problems, "The supported modules are "+supportedList(supported)+".")
+	}
+	return problems
+}
+
+// externalImports returns the sorted import paths of the solution that are neither part of
+// the standard library nor of the module of the solution.
+func externalImports(input_dir string, ownModule string, cfg ExerciseConfig) []string {
+	var imports []string
+	for _, pkg := range solutionPackageDirs(input_dir, cfg) {
+		for _, testFiles := range []bool{false, true} {
+			for _, file := range parseGoFiles(token.NewFileSet(), pkg, testFiles) {
+				for _, spec := range file.Imports {
+					importPath, err := strconv.Unquote(spec.Path.Value)
+					if err != nil || isStandardPackage(importPath) || inModule(importPath, ownModule) {
+						continue
+					}
+					imports = append(imports, importPath)
+				}
+			}
+		}
+	}
+	slices.Sort(imports)
+	return slices.Compact(imports)
+}
+
+// solutionPackageDirs returns the directories of the packages whose imports are checked.
+func solutionPackageDirs(input_dir string, cfg ExerciseConfig) []string {
+	if !cfg.AllPackages {
+		return []string{input_dir}
+	}
+	var dirs []string
+	_ = filepath.WalkDir(input_dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != input_dir && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		dirs = append(dirs, path)
+		return nil
+	})
+	return dirs
+}
+
+// isStandardPackage reports whether the import path belongs to the standard library,
+// whose import paths do not contain a dot in the first element.
+func isStandardPackage(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+func inModule(importPath string, modulePath string) bool {
+	return modulePath != "" && (importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/"))
+}
+
+// replacedModule applies the replace directives of the go.mod file to a required module.
+// It returns false if the module is replaced by a directory, which does not need to be downloaded.
+func replacedModule(goMod *modfile.File, mod module.Version) (module.Version, bool) {
+	for _, replace := range goMod.Replace {
+		if replace.Old.Path != mod.Path || replace.Old.Version != "" && replace.Old.Version != mod.Version {
+			continue
+		}
+		return replace.New, replace.New.Version != ""
+	}
+	return mod, true
+}
+
+// providingModule returns the required module with the longest path that contains the package.
+func providingModule(requires []*modfile.Require, importPath string) *modfile.Require {
+	var provider *modfile.Require
+	for _, req := range requires {
+		if inModule(importPath, req.Mod.Path) && (provider == nil || len(req.Mod.Path) > len(provider.Mod.Path)) {
+			provider = req
+		}
+	}
+	return provider
+}
+
+// moduleCache returns the directory of the module cache.
+func moduleCache() string {
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		return modCache
+	}
+	gopath, _, _ := strings.Cut(cmp.Or(os.Getenv("GOPATH"), build.Default.GOPATH), string(filepath.ListSeparator))
+	return filepath.Join(gopath, "pkg", "mod")
+}
+
+// moduleCached reports whether the download cache contains the file with the given
+// extension for the module, i.e. ".mod" for the go.mod file or ".zip" for the source code.
+func moduleCached(modCache string, mod module.Version, ext string) bool {
+	escapedPath, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return false
+	}
+	escapedVersion, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(modCache, "cache", "download", escapedPath, "@v", escapedVersion+ext))
+	return err == nil
+}
+
+// supportedModulesManifest lists the modules of the external packages that are downloaded
+// when the docker image is built. It is generated by cmd/gendeps.
+//
+//go:embed supported_modules.json
+var supportedModulesManifest []byte
+
+// supportedModule is an entry of supported_modules.json.
+type supportedModule struct {
+	Path     string   `json:"path"`
+	Version  string   `json:"version"`
+	Packages []string `json:"packages"`
+}
+
+// supportedModules returns the modules of the external packages supported on the track.
+func supportedModules() []supportedModule {
+	var manifest struct {
+		Modules []supportedModule `json:"modules"`
+	}
+	if err := json.Unmarshal(supportedModulesManifest, &manifest); err != nil {
+		log.Fatalf("failed to parse the supported modules: %s", err)
+	}
+	return manifest.Modules
+}
+
+func supportedList(supported []supportedModule) string {
+	var names []string
+	for _, mod := range supported {
+		names = append(names, mod.Path+" "+mod.Version)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,105 @@
+package testrunner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyModules(t *testing.T) {
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+	download := filepath.Join(modCache, "cache", "download")
+	writeFile(t, filepath.Join(download, "golang.org", "x", "text", "@v", "v0.7.0.mod"), "module golang.org/x/text\n")
+	writeFile(t, filepath.Join(download, "golang.org", "x", "text", "@v", "v0.7.0.zip"), "")
+	writeFile(t, filepath.Join(download, "golang.org", "x", "text", "@v", "v0.9.0.mod"), "module golang.org/x/text\n")
+	writeFile(t, filepath.Join(download, "golang.org", "x", "text", "@v", "v0.9.0.zip"), "")
+	writeFile(t, filepath.Join(download, "golang.org", "x", "exp", "@v", "v0.0.0-20221006183845-316c7553db56.mod"), "module golang.org/x/exp\n")
+	writeFile(t, filepath.Join(download, "github.com", "!burnt!sushi", "toml", "@v", "v1.2.0.mod"), "module github.com/BurntSushi/toml\n")
+	writeFile(t, filepath.Join(download, "github.com", "!burnt!sushi", "toml", "@v", "v1.2.0.zip"), "")
+	supported := "The supported modules are golang.org/x/exp v0.0.0-20221006183845-316c7553db56, golang.org/x/text v0.7.0."
+
+	tests := []struct {
+		name     string
+		goMod    string
+		solution string
+		expected []string
+	}{
+		{
+			name:     "no external packages",
+			goMod:    "module leap\n\ngo 1.26\n",
+			solution: "package leap\n\nimport (\n\t\"fmt\"\n\t\"leap/internal/calendar\"\n)\n",
+			expected: nil,
+		},
+		{
+			name:     "cached modules",
+			goMod:    "module leap\n\ngo 1.26\n\nrequire (\n\tgolang.org/x/text v0.7.0\n\tgithub.com/BurntSushi/toml v1.2.0 // indirect\n)\n",
+			solution: "package leap\n\nimport \"golang.org/x/text/language\"\n",
+			expected: nil,
+		},
+		{
+			name:     "module not in the cache",
+			goMod:    "module leap\n\ngo 1.26\n\nrequire golang.org/x/text v0.3.7\n",
+			solution: "package leap\n\nimport \"golang.org/x/text/language\"\n",
+			expected: []string{"module golang.org/x/text v0.3.7 is not supported on this track.", supported},
+		},
+		{
+			name:     "source code not in the cache",
+			goMod:    "module leap\n\ngo 1.26\n\nrequire golang.org/x/exp v0.0.0-20221006183845-316c7553db56\n",
+			solution: "package leap\n\nimport \"golang.org/x/exp/constraints\"\n",
+			expected: []string{"package golang.org/x/exp/constraints is not supported on this track, the source code of module golang.org/x/exp v0.0.0-20221006183845-316c7553db56 is not available.", supported},
+		},
+		{
+			name:     "cached version that is not supported",
+			goMod:    "module leap\n\ngo 1.26\n\nrequire golang.org/x/text v0.9.0\n",
+			solution: "package leap\n\nimport \"golang.org/x/text/language\"\n",
+			expected: []string{"package golang.org/x/text/language is not supported on this track in version v0.9.0 of module golang.org/x/text.", supported},
+		},
+		{
+			name:     "package without module",
+			goMod:    "module leap\n\ngo 1.26\n",
+			solution: "package leap\n\nimport \"github.com/example/emoji\"\n",
+			expected: []string{"package github.com/example/emoji is not supported on this track.", supported},
+		},
+		{
+			name:     "cached module that is not supported",
+			goMod:    "module leap\n\ngo 1.26\n\nrequire github.com/BurntSushi/toml v1.2.0\n",
+			solution: "package leap\n\nimport \"github.com/BurntSushi/toml\"\n",
+			expected: []string{"package github.com/BurntSushi/toml is not supported on this track.", supported},
+		},
+		{
+			name:     "module replaced by a directory",
+			goMod:    "module leap\n\ngo 1.26\n\nrequire golang.org/x/text v0.9.0\n\nreplace golang.org/x/text => ./text\n",
+			solution: "package leap\n\nimport \"golang.org/x/text/language\"\n",
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input_dir := t.TempDir()
+			writeFile(t, filepath.Join(input_dir, "go.mod"), tt.goMod)
+			writeFile(t, filepath.Join(input_dir, "leap.go"), tt.solution)
+
+			assert.Equal(t, tt.expected, verifyModules(input_dir, ExerciseConfig{}, &overlay{}))
+		})
+	}
+}
+
+func TestVerifyModules_NoGoMod(t *testing.T) {
+	assert.Nil(t, verifyModules(t.TempDir(), ExerciseConfig{}, &overlay{}))
+}
+
+func TestVerifyModules_Overlay(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "go.mod"), "module leap\n\ngo 1.26\n")
+	writeFile(t, filepath.Join(input_dir, "leap.go"), "package leap\n\nimport \"github.com/example/emoji\"\n")
+	o := &overlay{}
+	defer o.remove()
+	require.NoError(t, o.addFile(input_dir, "go.mod", []byte("module leap\n\ngo 1.26\n\nrequire github.com/example/emoji v1.0.0\n")))
+
+	problems := verifyModules(input_dir, ExerciseConfig{}, o)
+	require.NotEmpty(t, problems)
+	assert.Equal(t, "module github.com/example/emoji v1.0.0 is not supported on this track.", problems[0])
+}
@@ -0,0 +1,232 @@
+package testrunner
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"slices"
+)
+
+// Kinds of mutations.
+const (
+	// mutOperator replaces an arithmetic, logical or equality operator.
+	mutOperator = "operator"
+	// mutBoundary replaces a relational operator with its inclusive or exclusive variant.
+	mutBoundary = "boundary"
+	// mutReturnValue replaces a returned value with another value of the same type.
+	mutReturnValue = "return_value"
+)
+
+var operatorMutations = map[token.Token]token.Token{
+	token.ADD: token.SUB, token.SUB: token.ADD,
+	token.MUL: token.QUO, token.QUO: token.MUL, token.REM: token.MUL,
+	token.LAND: token.LOR, token.LOR: token.LAND,
+	token.EQL: token.NEQ, token.NEQ: token.EQL,
+	token.ADD_ASSIGN: token.SUB_ASSIGN, token.SUB_ASSIGN: token.ADD_ASSIGN,
+	token.MUL_ASSIGN: token.QUO_ASSIGN, token.QUO_ASSIGN: token.MUL_ASSIGN,
+	token.INC: token.DEC, token.DEC: token.INC,
+}
+
+var boundaryMutations = map[token.Token]token.Token{
+	token.LSS: token.LEQ, token.LEQ: token.LSS,
+	token.GTR: token.GEQ, token.GEQ: token.GTR,
+}
+
+// numericTypes are the predeclared types whose values are mutated to 0, or 1 if they are 0.
+var numericTypes = []string{
+	"int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+	"uintptr", "float32", "float64", "byte", "rune",
+}
+
+// mutant is a small change of the source code of a file that the tests should detect.
+type mutant struct {
+	// function is the name of the function containing the change, "Type.Method" for methods.
+	function    string
+	kind        string
+	position    token.Position
+	description string
+	// start and end are the offsets of the replaced source code.
+	start, end  int
+	replacement string
+}
+
+// apply returns the source code with the change of the mutant.
+func (m mutant) apply(src []byte) []byte {
+	return slices.Concat(src[:m.start], []byte(m.replacement), src[m.end:])
+}
+
+// generateMutants returns the mutants of the functions in the source code of a file,
+// ordered by their position. The mutants are derived from the syntax only, so some of them
+// may not compile, e.g. the subtraction of strings.
+func generateMutants(filename string, src []byte) ([]mutant, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var mutants []mutant
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		g := &mutantGenerator{fset: fset, src: src, function: functionName(fn)}
+		ast.Inspect(fn.Body, g.operators)
+		g.returnValues(fn.Body, resultTypes(fn.Type))
+		mutants = append(mutants, g.mutants...)
+	}
+	slices.SortStableFunc(mutants, func(a, b mutant) int {
+		return cmp.Compare(a.start, b.start)
+	})
+	return mutants, nil
+}
+
+// functionName returns the name of the function, prefixed with the receiver type for methods.
+func functionName(fn *ast.FuncDecl) string {
+	if fn.Recv != nil && len(fn.Recv.List) > 0 {
+		return receiverName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+type mutantGenerator struct {
+	fset     *token.FileSet
+	src      []byte
+	function string
+	mutants  []mutant
+}
+
+func (g *mutantGenerator) add(kind string, pos token.Pos, end token.Pos, replacement string) {
+	position := g.fset.Position(pos)
+	start, stop := position.Offset, g.fset.Position(end).Offset
+	g.mutants = append(g.mutants, mutant{
+		function:    g.function,
+		kind:        kind,
+		position:    position,
+		description: fmt.Sprintf("replaced `%s` with `%s`", shorten(string(g.src[start:stop])), shorten(replacement)),
+		start:       start,
+		end:         stop,
+		replacement: replacement,
+	})
+}
+
+// operators adds the operator and boundary mutations of a node.
+func (g *mutantGenerator) operators(node ast.Node) bool {
+	var op token.Token
+	var pos token.Pos
+	switch node := node.(type) {
+	case *ast.BinaryExpr:
+		if node.Op == token.ADD && (isStringLiteral(node.X) || isStringLiteral(node.Y)) {
+			// Strings can only be concatenated.
+			return true
+		}
+		op, pos = node.Op, node.OpPos
+	case *ast.AssignStmt:
+		op, pos = node.Tok, node.TokPos
+	case *ast.IncDecStmt:
+		op, pos = node.Tok, node.TokPos
+	default:
+		return true
+	}
+	if replacement, ok := operatorMutations[op]; ok {
+		g.add(mutOperator, pos, pos+token.Pos(len(op.String())), replacement.String())
+	}
+	if replacement, ok := boundaryMutations[op]; ok {
+		g.add(mutBoundary, pos, pos+token.Pos(len(op.String())), replacement.String())
+	}
+	return true
+}
+
+// returnValues adds the return value mutations of the return statements of a function body.
+// Function literals are skipped, their results have other types.
+func (g *mutantGenerator) returnValues(body *ast.BlockStmt, types []string) {
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(node.Results) != len(types) {
+				return true
+			}
+			for i, result := range node.Results {
+				if replacement, ok := g.returnValue(result, types[i]); ok {
+					g.add(mutReturnValue, result.Pos(), result.End(), replacement)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// returnValue returns another value of the given type for a returned expression.
+func (g *mutantGenerator) returnValue(result ast.Expr, typ string) (string, bool) {
+	switch {
+	case typ == "bool":
+		if ident, ok := result.(*ast.Ident); ok && ident.Name == "true" {
+			return "false", true
+		}
+		if ident, ok := result.(*ast.Ident); ok && ident.Name == "false" {
+			return "true", true
+		}
+		return "!(" + g.source(result) + ")", true
+	case slices.Contains(numericTypes, typ):
+		if lit, ok := result.(*ast.BasicLit); ok && (lit.Value == "0" || lit.Value == "0.0") {
+			return "1", true
+		}
+		return "0", true
+	case typ == "string":
+		if isStringLiteral(result) && len(result.(*ast.BasicLit).Value) == 2 {
+			return `"mutant"`, true
+		}
+		return `""`, true
+	case typ == "error":
+		if ident, ok := result.(*ast.Ident); ok && ident.Name == "nil" {
+			return "", false
+		}
+		return "nil", true
+	}
+	return "", false
+}
+
+func (g *mutantGenerator) source(expr ast.Expr) string {
+	return string(g.src[g.fset.Position(expr.Pos()).Offset:g.fset.Position(expr.End()).Offset])
+}
+
+// resultTypes returns the names of the result types of a function, one per result.
+// Types that are not identifiers, e.g. slices, are returned as empty strings.
+func resultTypes(fnType *ast.FuncType) []string {
+	var types []string
+	if fnType.Results == nil {
+		return nil
+	}
+	for _, field := range fnType.Results.List {
+		name := ""
+		if ident, ok := field.Type.(*ast.Ident); ok {
+			name = ident.Name
+		}
+		for range max(1, len(field.Names)) {
+			types = append(types, name)
+		}
+	}
+	return types
+}
+
+func isStringLiteral(expr ast.Expr) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	return ok && lit.Kind == token.STRING
+}
+
+// shorten limits source code in descriptions to a single short line.
+func shorten(code string) string {
+	const maxLength = 40
+	runes := []rune(code)
+	for i, r := range runes {
+		if r == '\n' || i == maxLength {
+			return string(runes[:i]) + "..."
+		}
+	}
+	return code
+}
@@ -0,0 +1,69 @@
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMutants(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "operators and boundaries",
+			src:  "package p\n\nfunc Sum(n int) int {\n\ts := 0\n\tfor i := 0; i < n; i++ {\n\t\ts += i\n\t}\n\treturn s\n}\n",
+			expected: []string{
+				"Sum boundary: replaced `<` with `<=`",
+				"Sum operator: replaced `++` with `--`",
+				"Sum operator: replaced `+=` with `-=`",
+				"Sum return_value: replaced `s` with `0`",
+			},
+		},
+		{
+			name: "return values",
+			src:  "package p\n\nfunc (c *Clock) Valid() (bool, error) {\n\tif c.h > 23 {\n\t\treturn false, errInvalid\n\t}\n\treturn true, nil\n}\n",
+			expected: []string{
+				"Clock.Valid boundary: replaced `>` with `>=`",
+				"Clock.Valid return_value: replaced `false` with `true`",
+				"Clock.Valid return_value: replaced `errInvalid` with `nil`",
+				"Clock.Valid return_value: replaced `true` with `false`",
+			},
+		},
+		{
+			name: "strings and function literals",
+			src:  "package p\n\nfunc Greet(name string) string {\n\tf := func() int { return 1 }\n\treturn \"Hello, \" + name + string(rune(f()))\n}\n",
+			expected: []string{
+				"Greet return_value: replaced `\"Hello, \" + name + string(rune(f()))` with `\"\"`",
+				"Greet operator: replaced `+` with `-`",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutants, err := generateMutants("p.go", []byte(tt.src))
+			require.NoError(t, err)
+
+			var actual []string
+			for _, m := range mutants {
+				actual = append(actual, m.function+" "+m.kind+": "+m.description)
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMutantApply(t *testing.T) {
+	src := []byte("package p\n\nfunc Positive(n int) bool {\n\treturn n > 0\n}\n")
+	mutants, err := generateMutants("p.go", src)
+	require.NoError(t, err)
+	require.Len(t, mutants, 2)
+
+	assert.Equal(t, "package p\n\nfunc Positive(n int) bool {\n\treturn !(n > 0)\n}\n", string(mutants[0].apply(src)))
+	assert.Equal(t, "package p\n\nfunc Positive(n int) bool {\n\treturn n >= 0\n}\n", string(mutants[1].apply(src)))
+	assert.Equal(t, 4, mutants[1].position.Line)
+	assert.Equal(t, 11, mutants[1].position.Column)
+}
@@ -0,0 +1,228 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// defaultMutantTimeout stops the tests of mutants that never finish, e.g. because a loop
+// counter is decremented instead of incremented.
+const defaultMutantTimeout = "10s"
+
+// mutationReport is the result of the mutation testing.
+type mutationReport struct {
+	// Status is pass if the tests detected every mutant that compiles, fail if some mutants
+	// survived and error if the tests do not pass for the example solution.
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	Mutants int    `json:"mutants"`
+	Killed  int    `json:"killed"`
+	// Invalid is the number of mutants that do not compile.
+	Invalid   int                `json:"invalid"`
+	Survived  int                `json:"survived"`
+	Functions []functionMutation `json:"functions"`
+}
+
+// functionMutation summarizes the mutants of a function of the example solution.
+type functionMutation struct {
+	Name     string            `json:"name"`
+	Mutants  int               `json:"mutants"`
+	Killed   int               `json:"killed"`
+	Survived []survivingMutant `json:"survived"`
+}
+
+// survivingMutant is a mutant the tests did not detect.
+type survivingMutant struct {
+	Position    string `json:"position"`
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+}
+
+// Mutate applies small changes, e.g. a replaced operator, to the example solution of an exercise
+// and runs the tests for every changed version. Changes that the tests do not detect point to
+// missing test cases. It is meant for maintainers assessing the tests of an exercise.
+func Mutate(input_dir string, opts Options) []byte {
+	report := mutate(input_dir, opts)
+	report.Message = newPathNormalizer(input_dir).normalize(report.Message)
+
+	bts, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		log.Fatalf("Failed to marshal the mutation report: %s", err)
+	}
+	return bts
+}
+
+func mutate(input_dir string, opts Options) *mutationReport {
+	report := &mutationReport{Status: statErr, Functions: []functionMutation{}}
+	cfg, _ := loadExerciseConfig(input_dir, opts.Config)
+	if cfg.Timeout == "" {
+		cfg.Timeout = defaultMutantTimeout
+	}
+	// The analyzers of go vet are not needed for mutants and may reject them.
+	cfg.TestingFlags = append(cfg.TestingFlags, "-vet=off", "-failfast")
+	limits := opts.OutputLimits
+	if limits.MaxOutputBytes == 0 {
+		limits.MaxOutputBytes = cfg.MaxOutputBytes
+	}
+
+	solutionFiles, exampleFiles, err := exerciseFiles(input_dir)
+	if err != nil {
+		report.Message = err.Error()
+		return report
+	}
+	solutionOverlay := prepareOverlay(input_dir, &cfg)
+	defer solutionOverlay.remove()
+	for _, name := range solutionFiles {
+		if err := solutionOverlay.deleteFile(input_dir, name); err != nil {
+			report.Message = err.Error()
+			return report
+		}
+	}
+
+	examples := map[string][]byte{}
+	for _, name := range exampleFiles {
+		src, err := os.ReadFile(filepath.Join(input_dir, name))
+		if err != nil {
+			report.Message = fmt.Sprintf("failed to read the example solution: %s", err)
+			return report
+		}
+		examples[name] = src
+		if err := solutionOverlay.addFile(input_dir, exampleTarget(name), src); err != nil {
+			report.Message = err.Error()
+			return report
+		}
+	}
+
+	if status, message, _ := runMutant(input_dir, &cfg, solutionOverlay, limits); status != statPass {
+		report.Message = "The tests do not pass for the example solution:\n" + message
+		return report
+	}
+
+	functionIndex := map[string]int{}
+	for _, name := range exampleFiles {
+		mutants, err := generateMutants(name, examples[name])
+		if err != nil {
+			report.Message = fmt.Sprintf("failed to parse the example solution: %s", err)
+			return report
+		}
+		for _, m := range mutants {
+			if err := solutionOverlay.addFile(input_dir, exampleTarget(name), m.apply(examples[name])); err != nil {
+				report.Message = err.Error()
+				return report
+			}
+			status, _, compiled := runMutant(input_dir, &cfg, solutionOverlay, limits)
+			if !compiled {
+				report.Invalid++
+				continue
+			}
+
+			idx, ok := functionIndex[m.function]
+			if !ok {
+				report.Functions = append(report.Functions, functionMutation{Name: m.function, Survived: []survivingMutant{}})
+				idx = len(report.Functions) - 1
+				functionIndex[m.function] = idx
+			}
+			function := &report.Functions[idx]
+			function.Mutants++
+			report.Mutants++
+			if status != statPass {
+				// A test failed, or the tests panicked or timed out.
+				function.Killed++
+				report.Killed++
+				continue
+			}
+			report.Survived++
+			function.Survived = append(function.Survived, survivingMutant{
+				Position:    fmt.Sprintf("%s:%d:%d", filepath.ToSlash(name), m.position.Line, m.position.Column),
+				Kind:        m.kind,
+				Description: m.description,
+			})
+		}
+		// The next file is mutated with the original version of this one.
+		if err := solutionOverlay.addFile(input_dir, exampleTarget(name), examples[name]); err != nil {
+			report.Message = err.Error()
+			return report
+		}
+	}
+
+	report.Status = statPass
+	if report.Survived > 0 {
+		report.Status = statFail
+	}
+	return report
+}
+
+// runMutant runs the tests with the files of the overlay and returns the status of the
+// test run, the message of the report and whether the tests were built.
+func runMutant(input_dir string, cfg *ExerciseConfig, o *overlay, limits OutputLimits) (string, string, bool) {
+	overlayFile, err := o.file()
+	if err != nil {
+		return statErr, err.Error(), false
+	}
+	cfg.overlayFile = overlayFile
+
+	testOutput, ok := runTests(input_dir, *cfg, limits.withDefaults())
+	if !ok {
+		return statErr, getStructureForTestsNotOk(testOutput, input_dir, reportVersion, *cfg).Message, false
+	}
+	report := getStructureForTestsOk(testOutput, input_dir, reportVersion, *cfg)
+	return report.Status, report.Message, true
+}
+
+// exampleTarget returns the name of the file in the solution directory that replaces
+// the solution with an example file, e.g. "example.go" for ".meta/example.go".
+func exampleTarget(name string) string {
+	return filepath.Base(name)
+}
+
+// exerciseFiles returns the solution files and the example files of the exercise relative
+// to the exercise directory, as listed in the files section of .meta/config.json.
+// Without the list, the solution consists of the non-test files in the exercise directory
+// and the example is .meta/example.go or .meta/exemplar.go.
+func exerciseFiles(input_dir string) ([]string, []string, error) {
+	var config struct {
+		Files struct {
+			Solution []string `json:"solution"`
+			Example  []string `json:"example"`
+			Exemplar []string `json:"exemplar"`
+		} `json:"files"`
+	}
+	content, err := os.ReadFile(filepath.Join(input_dir, ".meta", "config.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("config.json could not be read: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, &config); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse config.json: %w", err)
+		}
+	}
+
+	solution := config.Files.Solution
+	if len(solution) == 0 {
+		paths, _ := filepath.Glob(filepath.Join(input_dir, "*.go"))
+		for _, path := range paths {
+			if !strings.HasSuffix(path, "_test.go") {
+				solution = append(solution, filepath.Base(path))
+			}
+		}
+	}
+
+	example := slices.Concat(config.Files.Example, config.Files.Exemplar)
+	if len(example) == 0 {
+		for _, name := range []string{filepath.Join(".meta", "example.go"), filepath.Join(".meta", "exemplar.go")} {
+			if _, err := os.Stat(filepath.Join(input_dir, name)); err == nil {
+				example = append(example, name)
+			}
+		}
+	}
+	if len(example) == 0 {
+		return nil, nil, errors.New("the exercise has no example solution")
+	}
+	return solution, example, nil
+}
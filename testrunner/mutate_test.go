@@ -0,0 +1,107 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutate(t *testing.T) {
+	var report mutationReport
+	output := Mutate(filepath.Join("testdata", "practice", "mutation"), Options{})
+	require.NoError(t, json.Unmarshal(output, &report))
+
+	assert.Equal(t, mutationReport{
+		Status:   statFail,
+		Mutants:  11,
+		Killed:   10,
+		Survived: 1,
+		Functions: []functionMutation{
+			{
+				Name:    "IsLeapYear",
+				Mutants: 9,
+				Killed:  8,
+				Survived: []survivingMutant{
+					{Position: ".meta/example.go:5:29", Kind: mutOperator, Description: "replaced `%` with `*`"},
+				},
+			},
+			{Name: "DaysInYear", Mutants: 2, Killed: 2, Survived: []survivingMutant{}},
+		},
+	}, report)
+}
+
+func TestMutate_FailingExample(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "go.mod"), "module leap\n\ngo 1.26\n")
+	writeFile(t, filepath.Join(input_dir, "leap.go"), "package leap\n\nfunc IsLeapYear(year int) bool {\n\tpanic(\"Please implement\")\n}\n")
+	writeFile(t, filepath.Join(input_dir, ".meta", "example.go"), "package leap\n\nfunc IsLeapYear(year int) bool {\n\treturn year%4 == 0\n}\n")
+	writeFile(t, filepath.Join(input_dir, "leap_test.go"),
+		"package leap\n\nimport \"testing\"\n\nfunc TestIsLeapYear(t *testing.T) {\n\tif IsLeapYear(1900) {\n\t\tt.Fatal(\"1900 is no leap year\")\n\t}\n}\n")
+
+	var report mutationReport
+	require.NoError(t, json.Unmarshal(Mutate(input_dir, Options{}), &report))
+
+	assert.Equal(t, statErr, report.Status)
+	assert.Contains(t, report.Message, "The tests do not pass for the example solution")
+	assert.Zero(t, report.Mutants)
+}
+
+func TestExerciseFiles(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "leap.go"), "package leap\n")
+	writeFile(t, filepath.Join(input_dir, "leap_test.go"), "package leap\n")
+
+	_, _, err := exerciseFiles(input_dir)
+	assert.EqualError(t, err, "the exercise has no example solution")
+
+	writeFile(t, filepath.Join(input_dir, ".meta", "exemplar.go"), "package leap\n")
+	solution, example, err := exerciseFiles(input_dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leap.go"}, solution)
+	assert.Equal(t, []string{filepath.Join(".meta", "exemplar.go")}, example)
+
+	writeFile(t, filepath.Join(input_dir, ".meta", "config.json"),
+		`{"files": {"solution": ["leap.go", "calendar.go"], "example": [".meta/example.go"]}}`)
+	solution, example, err = exerciseFiles(input_dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"leap.go", "calendar.go"}, solution)
+	assert.Equal(t, []string{".meta/example.go"}, example)
+}
+
+func TestRunMutant(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "go.mod"), "module leap\n\ngo 1.26\n")
+	writeFile(t, filepath.Join(input_dir, "leap.go"), "package leap\n\nfunc IsLeapYear(year int) bool {\n\treturn year%4 == 0\n}\n")
+	writeFile(t, filepath.Join(input_dir, "leap_test.go"),
+		"package leap\n\nimport \"testing\"\n\nfunc TestIsLeapYear(t *testing.T) {\n\tif !IsLeapYear(2000) {\n\t\tt.Fatal(\"2000 is a leap year\")\n\t}\n}\n")
+	tests := []struct {
+		name     string
+		mutant   string
+		compiled bool
+	}{
+		{
+			name:     "panicking mutant",
+			mutant:   "package leap\n\nfunc IsLeapYear(year int) bool {\n\tpanic(year)\n}\n",
+			compiled: true,
+		},
+		{
+			name:     "mutant that does not compile",
+			mutant:   "package leap\n\nfunc IsLeapYear(year int) bool {\n\treturn year % \"4\" == 0\n}\n",
+			compiled: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &overlay{}
+			defer o.remove()
+			require.NoError(t, o.addFile(input_dir, "leap.go", []byte(tt.mutant)))
+
+			status, _, compiled := runMutant(input_dir, &ExerciseConfig{}, o, DefaultOutputLimits)
+			assert.NotEqual(t, statPass, status)
+			assert.Equal(t, tt.compiled, compiled)
+		})
+	}
+}
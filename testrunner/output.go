@@ -0,0 +1,87 @@
+package testrunner
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// OutputLimits restricts how much of the `go test --json` output is processed,
+// so that solutions printing huge amounts of text cannot break the test runner.
+// Zero values are replaced by the corresponding value of DefaultOutputLimits.
+type OutputLimits struct {
+	// MaxLineBytes is the maximum length of a single line of output, longer lines are truncated.
+	MaxLineBytes int
+	// MaxTestOutputBytes is the maximum amount of output collected for a single test.
+	MaxTestOutputBytes int
+	// MaxOutputBytes is the maximum amount of output in total. If it is exceeded,
+	// `go test` is stopped and only the results collected so far are reported.
+	MaxOutputBytes int64
+}
+
+var DefaultOutputLimits = OutputLimits{
+	MaxLineBytes:       1 << 20,
+	MaxTestOutputBytes: 256 << 10,
+	MaxOutputBytes:     16 << 20,
+}
+
+func (l OutputLimits) withDefaults() OutputLimits {
+	if l.MaxLineBytes <= 0 {
+		l.MaxLineBytes = DefaultOutputLimits.MaxLineBytes
+	}
+	if l.MaxTestOutputBytes <= 0 {
+		l.MaxTestOutputBytes = DefaultOutputLimits.MaxTestOutputBytes
+	}
+	if l.MaxOutputBytes <= 0 {
+		l.MaxOutputBytes = DefaultOutputLimits.MaxOutputBytes
+	}
+	return l
+}
+
+// readLine reads the next line without the line break. Only the first maxBytes
+// of the line are returned, the rest of the line is discarded.
+// It also returns the number of bytes that were consumed from the reader.
+func readLine(r *bufio.Reader, maxBytes int) (line []byte, truncated bool, consumed int, err error) {
+	for {
+		chunk, err := r.ReadSlice('\n')
+		consumed += len(chunk)
+		// The line break does not count towards the length of the line.
+		content := bytes.TrimSuffix(chunk, []byte("\n"))
+		if keep := maxBytes - len(line); keep < len(content) {
+			line = append(line, content[:max(keep, 0)]...)
+			truncated = true
+		} else {
+			line = append(line, content...)
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		return bytes.TrimRight(line, "\r\n"), truncated, consumed, err
+	}
+}
+
+var (
+	truncatedLineAction = regexp.MustCompile(`"Action":"(\w+)"`)
+	truncatedLineTest   = regexp.MustCompile(`"Test":("(?:[^"\\]|\\.)*")`)
+)
+
+// parseTruncatedLine recovers the test event from a json line that was too long
+// to be read completely. The output itself is replaced by a note.
+func parseTruncatedLine(line []byte, maxBytes int) testLine {
+	result := testLine{
+		Action: "output",
+		Output: fmt.Sprintf("[output line longer than %d bytes was truncated]\n", maxBytes),
+	}
+	if m := truncatedLineAction.FindSubmatch(line); m != nil {
+		result.Action = string(m[1])
+	}
+	if m := truncatedLineTest.FindSubmatch(line); m != nil {
+		if test, err := strconv.Unquote(string(m[1])); err == nil {
+			result.Test = test
+		}
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package testrunner
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLine(t *testing.T) {
+	const maxBytes = 20
+	tests := []struct {
+		name      string
+		input     string
+		line      string
+		truncated bool
+	}{
+		{
+			name:  "short line",
+			input: "short\nnext\n",
+			line:  "short",
+		},
+		{
+			name:  "exactly max bytes",
+			input: strings.Repeat("a", maxBytes) + "\nnext\n",
+			line:  strings.Repeat("a", maxBytes),
+		},
+		{
+			name:  "exactly max bytes without line break",
+			input: strings.Repeat("a", maxBytes),
+			line:  strings.Repeat("a", maxBytes),
+		},
+		{
+			name:      "one byte too long",
+			input:     strings.Repeat("a", maxBytes+1) + "\nnext\n",
+			line:      strings.Repeat("a", maxBytes),
+			truncated: true,
+		},
+		{
+			name:      "longer than the read buffer",
+			input:     strings.Repeat("a", 100) + "\nnext\n",
+			line:      strings.Repeat("a", maxBytes),
+			truncated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The smallest buffer of bufio forces long lines to be read in several chunks.
+			r := bufio.NewReaderSize(strings.NewReader(tt.input), 16)
+			line, truncated, consumed, _ := readLine(r, maxBytes)
+			assert.Equal(t, tt.line, string(line))
+			assert.Equal(t, tt.truncated, truncated)
+			assert.Equal(t, len(strings.SplitAfter(tt.input, "\n")[0]), consumed)
+
+			if strings.HasSuffix(tt.input, "next\n") {
+				next, _, _, err := readLine(r, maxBytes)
+				require.NoError(t, err)
+				assert.Equal(t, "next", string(next))
+			}
+		})
+	}
+}
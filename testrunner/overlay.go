@@ -0,0 +1,138 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// overlay replaces files of the solution for the go command without modifying the
+// solution directory, see the -overlay build flag. The solution directory can be read-only,
+// e.g. when the docker container runs with --read-only.
+type overlay struct {
+	// dir is the temporary directory with the replacement files and the overlay file.
+	dir string
+	// files is the number of replacement files in dir.
+	files int
+	// Replace maps the absolute paths of files of the solution to their replacements,
+	// an empty replacement deletes the file.
+	Replace map[string]string
+}
+
+// prepareOverlay repairs the files of the solution that would keep the tests from running.
+// The repaired files are added to the overlay, whose file is passed to every go command via
+// the exercise config. The overlay must be removed after the tests ran.
+func prepareOverlay(input_dir string, cfg *ExerciseConfig) *overlay {
+	o := &overlay{}
+	addGoModRepair(input_dir, cfg, o)
+	addGoSumRepair(input_dir, cfg, o)
+
+	overlayFile, err := o.file()
+	if err != nil {
+		log.Printf("warning: the repaired files are not used: %s", err)
+	}
+	cfg.overlayFile = overlayFile
+	return o
+}
+
+// addFile replaces the file at path in the solution directory with content.
+// The file does not need to exist in the solution directory.
+func (o *overlay) addFile(input_dir string, name string, content []byte) error {
+	paths, err := overlayPaths(input_dir, name)
+	if err != nil {
+		return err
+	}
+	replacement := o.Replace[paths[0]]
+	if replacement == "" {
+		if o.dir == "" {
+			dir, err := os.MkdirTemp("", "go-test-runner-overlay-")
+			if err != nil {
+				return fmt.Errorf("failed to create the overlay directory: %w", err)
+			}
+			o.dir = dir
+		}
+		replacement = filepath.Join(o.dir, fmt.Sprintf("%d-%s", o.files, filepath.Base(name)))
+		o.files++
+	}
+	if err := os.WriteFile(replacement, content, 0644); err != nil {
+		return fmt.Errorf("failed to write the overlay for %s: %w", name, err)
+	}
+	o.replace(paths, replacement)
+	return nil
+}
+
+// deleteFile hides the file at path in the solution directory from the go command.
+func (o *overlay) deleteFile(input_dir string, name string) error {
+	paths, err := overlayPaths(input_dir, name)
+	if err != nil {
+		return err
+	}
+	o.replace(paths, "")
+	return nil
+}
+
+func (o *overlay) replace(paths []string, replacement string) {
+	if o.Replace == nil {
+		o.Replace = map[string]string{}
+	}
+	for _, path := range paths {
+		o.Replace[path] = replacement
+	}
+}
+
+// overlayPaths returns the absolute paths under which the go command may look up a file of
+// the solution. If the solution directory is reached via a symlink, the go command can see
+// either the path with the symlink or the resolved path, depending on its working directory.
+func overlayPaths(input_dir string, name string) ([]string, error) {
+	dir, err := filepath.Abs(input_dir)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{filepath.Join(dir, name)}
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil && resolved != dir {
+		paths = append(paths, filepath.Join(resolved, name))
+	}
+	return paths, nil
+}
+
+// content returns the content of a file of the solution as the go command sees it,
+// i.e. the replacement if the overlay replaces the file.
+func (o *overlay) content(input_dir string, name string) ([]byte, error) {
+	paths, err := overlayPaths(input_dir, name)
+	if err != nil {
+		return nil, err
+	}
+	if replacement, ok := o.Replace[paths[0]]; ok {
+		if replacement == "" {
+			return nil, fmt.Errorf("%s is deleted by the overlay: %w", name, os.ErrNotExist)
+		}
+		return os.ReadFile(replacement)
+	}
+	return os.ReadFile(paths[0])
+}
+
+// file writes the overlay file for the -overlay build flag and returns its path.
+// It returns an empty path if no files are replaced.
+func (o *overlay) file() (string, error) {
+	if len(o.Replace) == 0 {
+		return "", nil
+	}
+	content, err := json.Marshal(o)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(o.dir, "overlay.json")
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write the overlay file: %w", err)
+	}
+	return path, nil
+}
+
+// remove deletes the replacement files.
+func (o *overlay) remove() {
+	if o.dir != "" {
+		_ = os.RemoveAll(o.dir)
+	}
+}
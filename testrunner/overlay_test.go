@@ -0,0 +1,72 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlay(t *testing.T) {
+	input_dir := t.TempDir()
+	o := &overlay{}
+
+	path, err := o.file()
+	require.NoError(t, err)
+	assert.Empty(t, path, "no overlay file without replaced files")
+
+	require.NoError(t, o.addFile(input_dir, "go.mod", []byte("module leap\n")))
+	path, err = o.file()
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var parsed struct{ Replace map[string]string }
+	require.NoError(t, json.Unmarshal(content, &parsed))
+	replacement, ok := parsed.Replace[filepath.Join(input_dir, "go.mod")]
+	require.True(t, ok)
+	replaced, err := os.ReadFile(replacement)
+	require.NoError(t, err)
+	assert.Equal(t, "module leap\n", string(replaced))
+	assert.NoFileExists(t, filepath.Join(input_dir, "go.mod"), "the solution directory is not modified")
+
+	o.remove()
+	assert.NoDirExists(t, filepath.Dir(path))
+}
+
+func TestOverlay_Content(t *testing.T) {
+	input_dir := t.TempDir()
+	writeFile(t, filepath.Join(input_dir, "go.mod"), "module leap\n\ngo 1.99\n")
+	o := &overlay{}
+	defer o.remove()
+
+	content, err := o.content(input_dir, "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, "module leap\n\ngo 1.99\n", string(content), "the file of the solution without replacement")
+
+	require.NoError(t, o.addFile(input_dir, "go.mod", []byte("module leap\n\ngo 1.26\n")))
+	content, err = o.content(input_dir, "go.mod")
+	require.NoError(t, err)
+	assert.Equal(t, "module leap\n\ngo 1.26\n", string(content))
+
+	_, err = o.content(input_dir, "go.sum")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestOverlay_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	input_dir := filepath.Join(dir, "solution")
+	require.NoError(t, os.Symlink(t.TempDir(), input_dir))
+	o := &overlay{}
+	defer o.remove()
+
+	require.NoError(t, o.addFile(input_dir, "go.mod", []byte("module leap\n")))
+
+	resolved, err := filepath.EvalSymlinks(input_dir)
+	require.NoError(t, err)
+	assert.Contains(t, o.Replace, filepath.Join(input_dir, "go.mod"))
+	assert.Contains(t, o.Replace, filepath.Join(resolved, "go.mod"))
+}
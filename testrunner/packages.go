@@ -0,0 +1,98 @@
+package testrunner
+
+import (
+	"bytes"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// solutionPackage is a package of the solution whose tests are run.
+type solutionPackage struct {
+	// importPath is empty if only the package in the solution directory is tested.
+	importPath string
+	dir        string
+}
+
+// testPattern returns the package pattern passed to the go command.
+func testPattern(cfg ExerciseConfig) string {
+	if cfg.AllPackages {
+		return "./..."
+	}
+	return "."
+}
+
+// solutionPackages returns the packages of the solution that contain tests.
+// Without allPackages in the exercise config, only the package in the solution directory is tested.
+func solutionPackages(input_dir string, cfg ExerciseConfig) []solutionPackage {
+	if !cfg.AllPackages {
+		return []solutionPackage{{dir: input_dir}}
+	}
+
+	var stderr bytes.Buffer
+	listCmd := goCommand(input_dir, cfg, "list", "-e", "-f", "{{.ImportPath}}\t{{.Dir}}", "./...")
+	listCmd.Stderr = &stderr
+	output, err := listCmd.Output()
+	if err != nil {
+		log.Printf("warning: failed to list the packages of the solution: %s %s", err, stderr.String())
+		return []solutionPackage{{dir: input_dir}}
+	}
+
+	var packages []solutionPackage
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		importPath, dir, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		testFiles, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+		if len(testFiles) > 0 {
+			packages = append(packages, solutionPackage{importPath: importPath, dir: dir})
+		}
+	}
+	return packages
+}
+
+// forPackage returns the output of a single package of the test run.
+// If importPath is empty, the output of all packages is returned.
+func (out *parsedTestOutput) forPackage(importPath string) *parsedTestOutput {
+	if importPath == "" {
+		return out
+	}
+	pkgOutput := *out
+	pkgOutput.testLines = slices.DeleteFunc(slices.Clone(out.testLines), func(line testLine) bool {
+		return line.Package != importPath
+	})
+	pkgOutput.pkgLevelMessages = out.pkgLevelMessagesByPackage[importPath]
+	return &pkgOutput
+}
+
+// packageTestResults collects the test results of a single package.
+func packageTestResults(parsedOutput *parsedTestOutput, pkg solutionPackage, cfg ExerciseConfig) []testResult {
+	pkgOutput := parsedOutput.forPackage(pkg.importPath)
+	rootLevelTests := FindAllRootLevelTests(FindTestFiles(pkg.dir))
+	tests := processTestResults(pkgOutput, rootLevelTests, cfg.TaskIDsEnabled)
+	for i := range tests {
+		tests[i].packagePath = pkg.importPath
+	}
+	return tests
+}
+
+// prefixPackagePaths adds the import path of the package to the names of the tests,
+// e.g. "leap/internal/calendar: TestDaysInYear". Tests of a single package keep their names.
+func prefixPackagePaths(tests []testResult) []testResult {
+	if !multiplePackages(tests) {
+		return tests
+	}
+	for i := range tests {
+		tests[i].Name = tests[i].packagePath + ": " + tests[i].Name
+	}
+	return tests
+}
+
+// multiplePackages reports whether the tests belong to more than one package.
+func multiplePackages(tests []testResult) bool {
+	return slices.ContainsFunc(tests, func(test testResult) bool {
+		return test.packagePath != tests[0].packagePath
+	})
+}
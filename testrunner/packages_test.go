@@ -0,0 +1,42 @@
+package testrunner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForPackage(t *testing.T) {
+	output := strings.Join([]string{
+		`{"Action":"run","Package":"leap","Test":"TestDaysInYear"}`,
+		`{"Action":"run","Package":"leap/internal/calendar","Test":"TestDaysInYear"}`,
+		`{"Action":"pass","Package":"leap","Test":"TestDaysInYear","Elapsed":0.01}`,
+		`{"Action":"fail","Package":"leap/internal/calendar","Test":"TestDaysInYear","Elapsed":0.02}`,
+		`{"Action":"output","Package":"leap/internal/calendar","Output":"FAIL\tleap/internal/calendar\n"}`,
+		`{"Action":"output","Package":"leap","Output":"ok  \tleap\n"}`,
+	}, "\n")
+	parsed, err := parseTestOutput(strings.NewReader(output), DefaultOutputLimits)
+	require.NoError(t, err)
+
+	calendar := parsed.forPackage("leap/internal/calendar")
+	assert.Len(t, calendar.testLines, 2)
+	assert.Equal(t, []string{"FAIL\tleap/internal/calendar\n"}, calendar.pkgLevelMessages)
+	assert.Equal(t, parsed, parsed.forPackage(""))
+
+	results := processTestResults(parsed.forPackage("leap"), nil, false)
+	assert.Equal(t, []testResult{{Name: "TestDaysInYear", Status: statPass, DurationMs: 10}}, results)
+}
+
+func TestPrefixPackagePaths(t *testing.T) {
+	tests := prefixPackagePaths([]testResult{
+		{Name: "TestDaysInYear", packagePath: "leap"},
+		{Name: "TestDaysInYear/ leap year", packagePath: "leap/internal/calendar"},
+	})
+	assert.Equal(t, "leap: TestDaysInYear", tests[0].Name)
+	assert.Equal(t, "leap/internal/calendar: TestDaysInYear/ leap year", tests[1].Name)
+
+	single := prefixPackagePaths([]testResult{{Name: "TestLeap"}, {Name: "TestNonLeap"}})
+	assert.Equal(t, "TestLeap", single[0].Name)
+}
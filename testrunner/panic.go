@@ -0,0 +1,177 @@
+package testrunner
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// panicStart matches the first line of the output produced by a panic or a fatal runtime error.
+	panicStart = regexp.MustCompile(`(?m)^(?:panic: |fatal error: |runtime: goroutine stack exceeds)`)
+	panicValue = regexp.MustCompile(`(?m)^((?:panic|fatal error): .*?)(?:\s*\[recovered(?:, repanicked)?\])?$`)
+	// timeoutPanic and outOfMemory match the panic values caused by exceeding the limits of the test run.
+	timeoutPanic = regexp.MustCompile(`^panic: test timed out after (\S+)$`)
+	outOfMemory  = regexp.MustCompile(`^fatal error: (?:runtime: )?(?:out of memory|cannot allocate memory)\b`)
+	// traceEnd matches the first line of test output after a goroutine dump.
+	traceEnd = regexp.MustCompile(`(?m)^(?:=== (?:RUN|PAUSE|CONT|NAME) |--- (?:FAIL|PASS|SKIP): )`)
+	// stackFrameFile matches the file and line of a stack frame, e.g. "\t/solution/leap.go:12 +0x1d".
+	stackFrameFile = regexp.MustCompile(`(?m)^\t(.+\.go):([0-9]+)(?:\s|$)`)
+)
+
+// summarizePanics replaces the goroutine dump in the message of failed tests that panicked
+// with a short summary of the panic value and the location in the solution, e.g.
+// "panic: index out of range [5] with length 3 at leap.go:12".
+// If includeTrace is set, the full trace is appended to the summary.
+// Passed tests are left as they are, a panic in their output was printed by the test itself.
+func summarizePanics(tests []testResult, input_dir string, includeTrace bool) []testResult {
+	solutionDir, err := filepath.Abs(input_dir)
+	if err != nil {
+		log.Printf("warning: failed to determine absolute path of %s: %s", input_dir, err)
+		return tests
+	}
+	for i := range tests {
+		if tests[i].Status != statFail && tests[i].Status != statErr {
+			continue
+		}
+		if summary, ok := summarizePanic(tests[i].Message, solutionDir, includeTrace); ok {
+			tests[i].Message = summary
+		}
+	}
+	return tests
+}
+
+// summarizePanic returns the summarized message and whether a panic was found at all.
+func summarizePanic(message string, solutionDir string, includeTrace bool) (string, bool) {
+	loc := panicStart.FindStringIndex(message)
+	if loc == nil {
+		return message, false
+	}
+	output, trace := message[:loc[0]], message[loc[0]:]
+	// The test output after the dump, e.g. the "--- FAIL" line, is kept.
+	var tail string
+	if end := traceEnd.FindStringIndex(trace); end != nil {
+		trace, tail = trace[:end[0]], trace[end[0]:]
+	}
+
+	match := panicValue.FindStringSubmatch(trace)
+	if match == nil {
+		return message, false
+	}
+	summary := match[1]
+	switch {
+	case timeoutPanic.MatchString(summary):
+		summary = timeoutPanic.ReplaceAllString(summary, "test exceeded the time limit of $1")
+	case outOfMemory.MatchString(summary):
+		summary = "test exceeded the memory limit"
+	}
+	if file, line, ok := panicLocation(trace, solutionDir); ok {
+		summary += fmt.Sprintf(" at %s:%s", file, line)
+	}
+
+	result := output + summary + "\n"
+	if includeTrace {
+		result += "\n" + trace
+	}
+	if tail != "" {
+		result += "\n" + tail
+	}
+	return result, true
+}
+
+// panicLocation finds the first stack frame of the trace that is located in a non-test file
+// of the solution. If there is none, the first frame in a test file of the solution is used.
+// The file name is returned relative to the solution directory.
+func panicLocation(trace string, solutionDir string) (string, string, bool) {
+	var testFile, testLine string
+	for _, match := range stackFrameFile.FindAllStringSubmatch(trace, -1) {
+		file, ok := solutionFile(match[1], solutionDir)
+		if !ok {
+			continue
+		}
+		if !strings.HasSuffix(file, "_test.go") {
+			return file, match[2], true
+		}
+		if testFile == "" {
+			testFile, testLine = file, match[2]
+		}
+	}
+	return testFile, testLine, testFile != ""
+}
+
+// testPanicked reports whether a test stopped the test binary with a panic or a fatal error.
+// Such a test has no final status and therefore still has the default status.
+func testPanicked(tests []testResult) bool {
+	return slices.ContainsFunc(tests, func(test testResult) bool {
+		return test.Status == statErr && panicStart.MatchString(test.Message)
+	})
+}
+
+var (
+	// packageSummaryLine matches the line printed by `go test` for the package after the tests.
+	packageSummaryLine = regexp.MustCompile(`(?m)^(?:FAIL|ok)\s+\S+(?:\s.*)?$`)
+	initFrame          = regexp.MustCompile(`(?m)^\S+\.init(?:\.[0-9]+)*\(`)
+	testMainFrame      = regexp.MustCompile(`(?m)^\S+\.TestMain\(`)
+)
+
+// packagePanic returns the trace of a panic found in the output that
+// does not belong to any test, or an empty string if there is none.
+func packagePanic(pkgOutput string) string {
+	loc := panicStart.FindStringIndex(pkgOutput)
+	if loc == nil {
+		return ""
+	}
+	trace := pkgOutput[loc[0]:]
+	if end := packageSummaryLine.FindStringIndex(trace); end != nil {
+		trace = trace[:end[0]]
+	}
+	return trace
+}
+
+// attributePackagePanic adds the trace of a panic that was reported outside of the
+// output of a test to the tests that were running at the time, see runningTests.
+// If no test was running, the panic happened in an init function or in TestMain.
+// It returns the message for the tests that were not executed, which mentions
+// the panic in that case.
+func attributePackagePanic(tests []testResult, running []string, trace string) ([]testResult, string) {
+	if len(running) > 0 {
+		for i := range tests {
+			if slices.Contains(running, tests[i].Name) {
+				tests[i].Message += "\n" + trace
+			}
+		}
+		return tests, notExecutedMessage
+	}
+
+	origin := "the package panicked"
+	switch {
+	case testMainFrame.MatchString(trace):
+		origin = "TestMain panicked"
+	case initFrame.MatchString(trace):
+		origin = "the package panicked during initialization"
+	}
+	return tests, fmt.Sprintf("This test was not executed because %s.\n%s", origin, trace)
+}
+
+// runningTests returns the tests that were running when the test binary stopped, i.e. the
+// tests that started or continued but did not finish. Parent tests are left out, they only
+// wait for their subtests.
+func runningTests(lines []testLine) []string {
+	var running []string
+	for _, line := range lines {
+		switch line.Action {
+		case "run", "cont":
+			if !slices.Contains(running, line.Test) {
+				running = append(running, line.Test)
+			}
+		case "pause", statPass, statFail, statSkip:
+			running = slices.DeleteFunc(running, func(name string) bool { return name == line.Test })
+		}
+	}
+	return slices.DeleteFunc(slices.Clone(running), func(name string) bool {
+		return slices.ContainsFunc(running, func(other string) bool { return strings.HasPrefix(other, name+"/") })
+	})
+}
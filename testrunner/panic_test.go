@@ -0,0 +1,184 @@
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizePanic(t *testing.T) {
+	trace := "panic: runtime error: index out of range [5] with length 3 [recovered, repanicked]\n" +
+		"\ngoroutine 7 [running]:\n" +
+		"testing.tRunner.func1.2({0x5c1e00, 0xc000014150})\n" +
+		"\t/usr/local/go/src/testing/testing.go:1632 +0x230\n" +
+		"leap.TestLeapYears(0xc000007d40)\n" +
+		"\t/solution/leap_test.go:8 +0x1d\n" +
+		"leap.IsLeapYear(...)\n" +
+		"\t/solution/leap.go:12\n"
+
+	tests := []struct {
+		name         string
+		message      string
+		includeTrace bool
+		expected     string
+		found        bool
+	}{
+		{
+			name:     "no panic",
+			message:  "\n=== RUN   TestLeapYears\n\n--- FAIL: TestLeapYears \n",
+			expected: "\n=== RUN   TestLeapYears\n\n--- FAIL: TestLeapYears \n",
+		},
+		{
+			name:     "panic in solution",
+			message:  "\n=== RUN   TestLeapYears\n\n" + trace,
+			expected: "\n=== RUN   TestLeapYears\n\npanic: runtime error: index out of range [5] with length 3 at leap.go:12\n",
+			found:    true,
+		},
+		{
+			name:         "panic with trace",
+			message:      trace,
+			includeTrace: true,
+			expected:     "panic: runtime error: index out of range [5] with length 3 at leap.go:12\n\n" + trace,
+			found:        true,
+		},
+		{
+			name:     "test output after the trace",
+			message:  "\n=== RUN   TestLeapYears\n\n" + trace + "\n--- FAIL: TestLeapYears \n",
+			expected: "\n=== RUN   TestLeapYears\n\npanic: runtime error: index out of range [5] with length 3 at leap.go:12\n\n--- FAIL: TestLeapYears \n",
+			found:    true,
+		},
+		{
+			name: "panic in test file only",
+			message: "panic: boom\n\ngoroutine 7 [running]:\n" +
+				"leap.TestLeapYears(0xc000007d40)\n\t/solution/leap_test.go:8 +0x1d\n",
+			expected: "panic: boom at leap_test.go:8\n",
+			found:    true,
+		},
+		{
+			name: "timeout",
+			message: "panic: test timed out after 1s\n\trunning tests:\n\t\tTestLeapYears (1s)\n\ngoroutine 7 [running]:\n" +
+				"leap.IsLeapYear(...)\n\t/solution/leap.go:7\n",
+			expected: "test exceeded the time limit of 1s at leap.go:7\n",
+			found:    true,
+		},
+		{
+			name:     "out of memory",
+			message:  "fatal error: runtime: out of memory\n\ngoroutine 7 [running]:\nleap.IsLeapYear(...)\n\t/solution/leap.go:7\n",
+			expected: "test exceeded the memory limit at leap.go:7\n",
+			found:    true,
+		},
+		{
+			name:     "memory limit of the test binary",
+			message:  "fatal error: runtime: cannot allocate memory\n\ngoroutine 7 [running]:\nleap.IsLeapYear(...)\n\t/solution/leap.go:7\n",
+			expected: "test exceeded the memory limit at leap.go:7\n",
+			found:    true,
+		},
+		{
+			name: "fatal error outside of solution",
+			message: "runtime: goroutine stack exceeds 1000000000-byte limit\n" +
+				"fatal error: stack overflow\n\nruntime stack:\n" +
+				"runtime.throw({0x5e1b3c?, 0x0?})\n\t/usr/local/go/src/runtime/panic.go:1047 +0x5d\n",
+			expected: "fatal error: stack overflow\n",
+			found:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := summarizePanic(tt.message, "/solution", tt.includeTrace)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestSummarizePanics(t *testing.T) {
+	message := "\n=== RUN   TestLeapYears\n\npanic: boom\n\ngoroutine 7 [running]:\n" +
+		"leap.IsLeapYear(...)\n\t/solution/leap.go:7\n"
+	tests := []testResult{
+		{Name: "TestPass", Status: statPass, Message: message},
+		{Name: "TestFail", Status: statFail, Message: message},
+		{Name: "TestErr", Status: statErr, Message: message},
+	}
+
+	summarized := summarizePanics(tests, "/solution", false)
+	assert.Equal(t, message, summarized[0].Message)
+	assert.Equal(t, "\n=== RUN   TestLeapYears\n\npanic: boom at leap.go:7\n", summarized[1].Message)
+	assert.Equal(t, "\n=== RUN   TestLeapYears\n\npanic: boom at leap.go:7\n", summarized[2].Message)
+}
+
+func TestAttributePackagePanic(t *testing.T) {
+	initTrace := "panic: boom\n\ngoroutine 1 [running]:\npov.New(...)\n\t/solution/pov.go:9\npov.init()\n\t/solution/helper_test.go:17 +0x25\n"
+	testMainTrace := "panic: boom\n\ngoroutine 1 [running]:\nleap.TestMain(0xc000100000)\n\t/solution/leap_test.go:10 +0x1d\n"
+
+	tests := []struct {
+		name               string
+		tests              []testResult
+		running            []string
+		trace              string
+		expectedTests      []testResult
+		expectedNotExecMsg string
+	}{
+		{
+			name:               "panic in init",
+			tests:              []testResult{},
+			trace:              initTrace,
+			expectedTests:      []testResult{},
+			expectedNotExecMsg: "This test was not executed because the package panicked during initialization.\n" + initTrace,
+		},
+		{
+			name:               "panic in TestMain",
+			tests:              []testResult{},
+			trace:              testMainTrace,
+			expectedTests:      []testResult{},
+			expectedNotExecMsg: "This test was not executed because TestMain panicked.\n" + testMainTrace,
+		},
+		{
+			name: "panic while a test was running",
+			tests: []testResult{
+				{Name: "TestA", Status: statPass, Message: "\n=== RUN   TestA\n"},
+				{Name: "TestB", Status: statErr, Message: "\n=== RUN   TestB\n"},
+				{Name: "TestB/first", Status: statErr, Message: "\n=== RUN   TestB/first\n"},
+				{Name: "TestB/second", Status: statErr, Message: "\n=== RUN   TestB/second\n"},
+			},
+			running: []string{"TestB/second"},
+			trace:   initTrace,
+			expectedTests: []testResult{
+				{Name: "TestA", Status: statPass, Message: "\n=== RUN   TestA\n"},
+				{Name: "TestB", Status: statErr, Message: "\n=== RUN   TestB\n"},
+				{Name: "TestB/first", Status: statErr, Message: "\n=== RUN   TestB/first\n"},
+				{Name: "TestB/second", Status: statErr, Message: "\n=== RUN   TestB/second\n\n" + initTrace},
+			},
+			expectedNotExecMsg: notExecutedMessage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, notExecMsg := attributePackagePanic(tt.tests, tt.running, tt.trace)
+			assert.Equal(t, tt.expectedTests, got)
+			assert.Equal(t, tt.expectedNotExecMsg, notExecMsg)
+		})
+	}
+}
+
+func TestRunningTests(t *testing.T) {
+	lines := []testLine{
+		{Action: "run", Test: "TestA"},
+		{Action: "pass", Test: "TestA"},
+		{Action: "run", Test: "TestB"},
+		{Action: "run", Test: "TestB/first"},
+		{Action: "fail", Test: "TestB/first"},
+		{Action: "run", Test: "TestB/second"},
+		{Action: "run", Test: "TestC"},
+		{Action: "pause", Test: "TestC"},
+		{Action: "run", Test: "TestD"},
+		{Action: "pause", Test: "TestD"},
+		{Action: "cont", Test: "TestD"},
+	}
+	assert.Equal(t, []string{"TestB/second", "TestD"}, runningTests(lines))
+}
+
+func TestPackagePanic(t *testing.T) {
+	trace := "panic: boom\n\ngoroutine 1 [running]:\nleap.TestMain(0xc000100000)\n\t/solution/leap_test.go:10 +0x1d\n"
+	assert.Equal(t, trace, packagePanic(trace+"FAIL\tleap\t0.004s\n"))
+	assert.Equal(t, "", packagePanic("PASS\nok  \tleap\t0.004s\n"))
+}
@@ -0,0 +1,96 @@
+package testrunner
+
+import (
+	"cmp"
+	"go/build"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// relativeGoFile matches references to go files relative to the current directory,
+// e.g. "./leap.go:12:3" in compile errors.
+var relativeGoFile = regexp.MustCompile(`(^|[\s'"(])\.[/\\]+([^\s'"()]+\.go:[0-9])`)
+
+// buildDir matches the temporary directories of the go command, e.g. for the generated
+// "/tmp/go-build1234/b001/_testmain.go".
+var buildDir = regexp.MustCompile(`[^\s'"()]*go-build[0-9]+[/\\]+b[0-9]+[/\\]+`)
+
+// pathNormalizer rewrites the paths in messages so they do not depend on where the
+// solution, the go installation and the module cache are located. Files of the solution
+// are shown relative to the solution directory, e.g. "leap.go:12", files of the standard
+// library and of modules like with -trimpath, e.g. "runtime/panic.go:1243".
+type pathNormalizer struct {
+	replacer *strings.Replacer
+}
+
+func newPathNormalizer(input_dir string) pathNormalizer {
+	type replacement struct{ path, with string }
+	var replacements []replacement
+	add := func(path string, with string) {
+		if path == "" || path == "." {
+			return
+		}
+		paths := []string{path}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			paths = append(paths, resolved)
+		}
+		for _, p := range paths {
+			replacements = append(replacements, replacement{p, with})
+			if slashed := filepath.ToSlash(p); slashed != p {
+				replacements = append(replacements, replacement{slashed, with})
+			}
+		}
+	}
+
+	if goExe, err := exec.LookPath("go"); err == nil {
+		add(goExe, "go")
+	}
+	if solutionDir, err := filepath.Abs(input_dir); err == nil {
+		add(solutionDir+string(filepath.Separator), "")
+	}
+	goRoot := cmp.Or(os.Getenv("GOROOT"), build.Default.GOROOT)
+	add(filepath.Join(goRoot, "src")+string(filepath.Separator), "")
+	for _, modCache := range filepath.SplitList(cmp.Or(os.Getenv("GOMODCACHE"), filepath.Join(build.Default.GOPATH, "pkg", "mod"))) {
+		add(modCache+string(filepath.Separator), "")
+	}
+
+	// Longer paths first, so the solution directory is replaced before a parent directory.
+	slices.SortStableFunc(replacements, func(a, b replacement) int {
+		return cmp.Compare(len(b.path), len(a.path))
+	})
+	var oldnew []string
+	for _, r := range replacements {
+		oldnew = append(oldnew, r.path, r.with)
+	}
+	return pathNormalizer{replacer: strings.NewReplacer(oldnew...)}
+}
+
+// normalize rewrites the paths in a single message.
+func (n pathNormalizer) normalize(message string) string {
+	message = n.replacer.Replace(message)
+	message = buildDir.ReplaceAllString(message, "")
+	return relativeGoFile.ReplaceAllString(message, "$1$2")
+}
+
+// normalizeReport rewrites the paths in all messages of the report.
+func (n pathNormalizer) normalizeReport(report *testReport) {
+	report.Message = n.normalize(report.Message)
+	for i := range report.Tests {
+		report.Tests[i].Message = n.normalize(report.Tests[i].Message)
+		n.normalizeDataRaces(report.Tests[i].DataRaces)
+	}
+	for i := range report.Warnings {
+		report.Warnings[i].Message = n.normalize(report.Warnings[i].Message)
+	}
+	n.normalizeDataRaces(report.DataRaces)
+}
+
+func (n pathNormalizer) normalizeDataRaces(races []dataRace) {
+	for i := range races {
+		races[i].Raw = n.normalize(races[i].Raw)
+	}
+}
@@ -0,0 +1,54 @@
+package testrunner
+
+import (
+	"cmp"
+	"go/build"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathNormalizer(t *testing.T) {
+	solutionDir := t.TempDir()
+	goRoot := cmp.Or(os.Getenv("GOROOT"), build.Default.GOROOT)
+	normalizer := newPathNormalizer(solutionDir)
+
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{
+			name:     "solution file",
+			message:  "panic: boom\n\nleap.IsLeapYear(...)\n\t" + filepath.Join(solutionDir, "leap.go") + ":12 +0x1d\n",
+			expected: "panic: boom\n\nleap.IsLeapYear(...)\n\tleap.go:12 +0x1d\n",
+		},
+		{
+			name:     "standard library file",
+			message:  "\t" + filepath.Join(goRoot, "src", "runtime", "panic.go") + ":1243 +0x48",
+			expected: "\truntime/panic.go:1243 +0x48",
+		},
+		{
+			name:     "compile error",
+			message:  "# leap [leap.test]\n./leap_test.go:18:17: undefined: IsLeapYear\n",
+			expected: "# leap [leap.test]\nleap_test.go:18:17: undefined: IsLeapYear\n",
+		},
+		{
+			name:     "generated test main",
+			message:  "main.main()\n\t/tmp/go-build1234/b001/_testmain.go:46 +0x25",
+			expected: "main.main()\n\t_testmain.go:46 +0x25",
+		},
+		{
+			name:     "unrelated text",
+			message:  "expected ./leap to be 1.5, got 2",
+			expected: "expected ./leap to be 1.5, got 2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, filepath.ToSlash(tt.expected), filepath.ToSlash(normalizer.normalize(tt.message)))
+		})
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package testrunner
+
+import "os/exec"
+
+func startProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
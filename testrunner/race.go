@@ -0,0 +1,153 @@
+package testrunner
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// dataRace is the structured form of a report of the race detector.
+// Only stack frames located in the files of the solution are kept.
+type dataRace struct {
+	Accesses   []raceAccess    `json:"accesses"`
+	Goroutines []raceGoroutine `json:"goroutines,omitempty"`
+	// Raw is the original report of the race detector.
+	Raw string `json:"raw"`
+}
+
+// raceAccess is one of the conflicting memory accesses, e.g. "Read" or "Previous write".
+type raceAccess struct {
+	Access    string       `json:"access"`
+	Goroutine string       `json:"goroutine"`
+	Frames    []stackFrame `json:"frames"`
+}
+
+// raceGoroutine describes where a goroutine involved in the data race was created.
+type raceGoroutine struct {
+	ID        int          `json:"id"`
+	State     string       `json:"state"`
+	CreatedAt []stackFrame `json:"created_at"`
+}
+
+type stackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"` // relative to the solution directory
+	Line     int    `json:"line"`
+}
+
+var (
+	raceBlock = regexp.MustCompile(`(?s)==================\n+WARNING: DATA RACE\n(.*?)\n*==================\n?`)
+	// raceAccessHeader matches e.g. "Previous write at 0x00c000016178 by goroutine 7:".
+	raceAccessHeader = regexp.MustCompile(`^(.+?) at 0x[0-9a-f]+ by (.+):$`)
+	// raceGoroutineHeader matches e.g. "Goroutine 7 (running) created at:".
+	raceGoroutineHeader = regexp.MustCompile(`^Goroutine ([0-9]+) \((.+)\) created at:$`)
+	raceFrameFile       = regexp.MustCompile(`^\s+(.+\.go):([0-9]+)(?:\s|$)`)
+)
+
+// summarizeDataRaces replaces the reports of the race detector in the messages
+// of the tests with a concise summary and stores the parsed reports in the test results.
+func summarizeDataRaces(tests []testResult, input_dir string) []testResult {
+	solutionDir, err := filepath.Abs(input_dir)
+	if err != nil {
+		log.Printf("warning: failed to determine absolute path of %s: %s", input_dir, err)
+		return tests
+	}
+	for i := range tests {
+		tests[i].Message, tests[i].DataRaces = summarizeDataRacesInMessage(tests[i].Message, solutionDir)
+	}
+	return tests
+}
+
+// summarizeDataRacesInMessage returns the message with every race report replaced
+// by its summary, together with the parsed reports.
+func summarizeDataRacesInMessage(message string, solutionDir string) (string, []dataRace) {
+	matches := raceBlock.FindAllStringSubmatchIndex(message, -1)
+	if matches == nil {
+		return message, nil
+	}
+
+	var races []dataRace
+	var result strings.Builder
+	last := 0
+	for _, m := range matches {
+		race := parseDataRace(message[m[2]:m[3]], solutionDir)
+		races = append(races, race)
+		result.WriteString(message[last:m[0]])
+		result.WriteString(race.summary())
+		last = m[1]
+	}
+	result.WriteString(message[last:])
+	return result.String(), races
+}
+
+// parseDataRace parses the body of a race report, i.e. the part between
+// the "WARNING: DATA RACE" line and the closing separator.
+func parseDataRace(body string, solutionDir string) dataRace {
+	race := dataRace{}
+	var raw []string
+	var frames *[]stackFrame
+	var function string
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		raw = append(raw, line)
+
+		if m := raceAccessHeader.FindStringSubmatch(line); m != nil {
+			race.Accesses = append(race.Accesses, raceAccess{Access: m[1], Goroutine: m[2], Frames: []stackFrame{}})
+			frames = &race.Accesses[len(race.Accesses)-1].Frames
+			continue
+		}
+		if m := raceGoroutineHeader.FindStringSubmatch(line); m != nil {
+			id, _ := strconv.Atoi(m[1])
+			race.Goroutines = append(race.Goroutines, raceGoroutine{ID: id, State: m[2], CreatedAt: []stackFrame{}})
+			frames = &race.Goroutines[len(race.Goroutines)-1].CreatedAt
+			continue
+		}
+		if m := raceFrameFile.FindStringSubmatch(line); m != nil {
+			file, ok := solutionFile(m[1], solutionDir)
+			if ok && frames != nil {
+				lineNumber, _ := strconv.Atoi(m[2])
+				*frames = append(*frames, stackFrame{Function: function, File: file, Line: lineNumber})
+			}
+			continue
+		}
+		function = strings.TrimSuffix(strings.TrimSpace(line), "()")
+	}
+
+	race.Raw = "WARNING: DATA RACE\n" + strings.Join(raw, "\n") + "\n"
+	return race
+}
+
+// summary describes the data race with one line per memory access and goroutine,
+// each pointing to the first location in the solution.
+func (r dataRace) summary() string {
+	lines := []string{"WARNING: DATA RACE"}
+	for _, access := range r.Accesses {
+		lines = append(lines, fmt.Sprintf("%s by %s%s", access.Access, access.Goroutine, frameLocation(access.Frames)))
+	}
+	for _, goroutine := range r.Goroutines {
+		lines = append(lines, fmt.Sprintf("Goroutine %d created%s", goroutine.ID, frameLocation(goroutine.CreatedAt)))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func frameLocation(frames []stackFrame) string {
+	if len(frames) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" at %s:%d in %s", frames[0].File, frames[0].Line, frames[0].Function)
+}
+
+// solutionFile returns the name of the file relative to the solution directory
+// if it is located there.
+func solutionFile(path string, solutionDir string) (string, bool) {
+	path = filepath.FromSlash(path)
+	if filepath.Dir(path) != solutionDir {
+		return "", false
+	}
+	return filepath.Base(path), true
+}
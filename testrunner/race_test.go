@@ -0,0 +1,92 @@
+package testrunner
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeDataRacesInMessage(t *testing.T) {
+	report := "==================\n" +
+		"WARNING: DATA RACE\n" +
+		"Read at 0x00c000016178 by goroutine 8:\n" +
+		"  account.(*Account).Balance()\n" +
+		"      /solution/bank_account.go:24 +0x6a\n" +
+		"  account.TestConcDeposit.func3()\n" +
+		"      /solution/bank_account_test.go:46 +0x8d\n" +
+		"\n" +
+		"Previous write at 0x00c000016178 by goroutine 7:\n" +
+		"  sync/atomic.AddInt64()\n" +
+		"      /usr/local/go/src/runtime/race_amd64.s:289 +0xb\n" +
+		"  account.(*Account).Deposit()\n" +
+		"      /solution/bank_account.go:37 +0x144\n" +
+		"\n" +
+		"Goroutine 8 (running) created at:\n" +
+		"  account.TestConcDeposit()\n" +
+		"      /solution/bank_account_test.go:44 +0x1f1\n" +
+		"  testing.tRunner()\n" +
+		"      /usr/local/go/src/testing/testing.go:2193 +0x21c\n" +
+		"==================\n"
+	message := "\n=== RUN   TestConcDeposit\n\n" + report + "\n    testing.go:1865: race detected during execution of test\n"
+
+	got, races := summarizeDataRacesInMessage(message, "/solution")
+
+	assert.Equal(t, "\n=== RUN   TestConcDeposit\n\n"+
+		"WARNING: DATA RACE\n"+
+		"Read by goroutine 8 at bank_account.go:24 in account.(*Account).Balance\n"+
+		"Previous write by goroutine 7 at bank_account.go:37 in account.(*Account).Deposit\n"+
+		"Goroutine 8 created at bank_account_test.go:44 in account.TestConcDeposit\n"+
+		"\n    testing.go:1865: race detected during execution of test\n", got)
+
+	assert.Equal(t, []dataRace{{
+		Accesses: []raceAccess{
+			{
+				Access:    "Read",
+				Goroutine: "goroutine 8",
+				Frames: []stackFrame{
+					{Function: "account.(*Account).Balance", File: "bank_account.go", Line: 24},
+					{Function: "account.TestConcDeposit.func3", File: "bank_account_test.go", Line: 46},
+				},
+			},
+			{
+				Access:    "Previous write",
+				Goroutine: "goroutine 7",
+				Frames: []stackFrame{
+					{Function: "account.(*Account).Deposit", File: "bank_account.go", Line: 37},
+				},
+			},
+		},
+		Goroutines: []raceGoroutine{
+			{
+				ID:    8,
+				State: "running",
+				CreatedAt: []stackFrame{
+					{Function: "account.TestConcDeposit", File: "bank_account_test.go", Line: 44},
+				},
+			},
+		},
+		Raw: "WARNING: DATA RACE\n" +
+			"Read at 0x00c000016178 by goroutine 8:\n" +
+			"  account.(*Account).Balance()\n" +
+			"      /solution/bank_account.go:24 +0x6a\n" +
+			"  account.TestConcDeposit.func3()\n" +
+			"      /solution/bank_account_test.go:46 +0x8d\n" +
+			"Previous write at 0x00c000016178 by goroutine 7:\n" +
+			"  sync/atomic.AddInt64()\n" +
+			"      /usr/local/go/src/runtime/race_amd64.s:289 +0xb\n" +
+			"  account.(*Account).Deposit()\n" +
+			"      /solution/bank_account.go:37 +0x144\n" +
+			"Goroutine 8 (running) created at:\n" +
+			"  account.TestConcDeposit()\n" +
+			"      /solution/bank_account_test.go:44 +0x1f1\n" +
+			"  testing.tRunner()\n" +
+			"      /usr/local/go/src/testing/testing.go:2193 +0x21c\n",
+	}}, races)
+}
+
+func TestSummarizeDataRacesInMessage_NoRace(t *testing.T) {
+	message := "\n=== RUN   TestConcDeposit\n\n--- PASS: TestConcDeposit \n"
+	got, races := summarizeDataRacesInMessage(message, "/solution")
+	assert.Equal(t, message, got)
+	assert.Nil(t, races)
+}
@@ -0,0 +1,278 @@
+package testrunner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"log"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// testTypeError matches compile errors in test files, e.g.
+// "./leap_test.go:6:23: too many arguments in call to IsLeapYear".
+var testTypeError = regexp.MustCompile(`(?m)^\S+_test\.go:\d+:\d+: (.*)$`)
+
+// hasTestTypeErrors reports whether the build output contains compile errors in test files
+// that signature hints can explain. Undefined identifiers are left to missingDeclarationHints.
+func hasTestTypeErrors(buildOutput string) bool {
+	for _, match := range testTypeError.FindAllStringSubmatch(buildOutput, -1) {
+		if !strings.HasPrefix(match[1], "undefined: ") {
+			return true
+		}
+	}
+	return false
+}
+
+// signatureHints type checks the solution together with its tests and explains calls in the
+// tests that do not match the signature of the called function of the solution.
+// The signature expected by the tests is derived from the arguments of the call and from
+// the values the results are compared with. Only the first mismatching call of every
+// function is reported.
+func signatureHints(input_dir string, cfg ExerciseConfig) []string {
+	solutionDir, err := filepath.Abs(input_dir)
+	if err != nil {
+		log.Printf("warning: failed to determine absolute path of %s: %s", input_dir, err)
+		return nil
+	}
+	loadCfg := &packages.Config{
+		Mode:       packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Dir:        solutionDir,
+		Env:        goEnv(cfg),
+		BuildFlags: buildFlags(cfg),
+		Tests:      true,
+	}
+	pkgs, err := packages.Load(loadCfg, ".")
+	if err != nil {
+		log.Printf("warning: no signature hints, failed to load the solution: %s", err)
+		return nil
+	}
+
+	// The test variant of the package, e.g. "leap [leap.test]", contains the solution and
+	// the tests of the same package. Tests in an external test package are not checked.
+	idx := slices.IndexFunc(pkgs, func(pkg *packages.Package) bool {
+		return strings.HasSuffix(pkg.ID, ".test]") && !strings.HasSuffix(pkg.Name, "_test")
+	})
+	if idx < 0 {
+		return nil
+	}
+	pkg := pkgs[idx]
+	if len(pkg.TypeErrors) == 0 || pkg.Types == nil || pkg.TypesInfo == nil {
+		return nil
+	}
+
+	checker := signatureChecker{fset: pkg.Fset, info: pkg.TypesInfo, pkg: pkg.Types, reported: map[*types.Func]bool{}}
+	var hints []string
+	for _, file := range pkg.Syntax {
+		if !strings.HasSuffix(pkg.Fset.Position(file.Pos()).Filename, "_test.go") {
+			continue
+		}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				hints = append(hints, checker.checkCalls(fn.Body)...)
+			}
+		}
+	}
+	return hints
+}
+
+type signatureChecker struct {
+	fset     *token.FileSet
+	info     *types.Info
+	pkg      *types.Package
+	reported map[*types.Func]bool
+}
+
+// checkCalls checks the calls of solution functions in the body of a test function.
+func (c *signatureChecker) checkCalls(body *ast.BlockStmt) []string {
+	// results maps the variables that are assigned the results of a call to the call
+	// and the index of the result.
+	type resultUse struct {
+		call  *ast.CallExpr
+		index int
+	}
+	results := map[types.Object]resultUse{}
+	// compared contains the types of the values that the results of a call are compared with.
+	compared := map[*ast.CallExpr]map[int]types.Type{}
+	var calls []*ast.CallExpr
+
+	compare := func(operand ast.Expr, other ast.Expr) {
+		operand = ast.Unparen(operand)
+		t := c.typeOf(other)
+		if ident, ok := operand.(*ast.Ident); ok && ident.Name == "err" && c.info.Types[other].IsNil() {
+			// A result that is compared with nil and named err is most likely an error.
+			t = types.Universe.Lookup("error").Type()
+		}
+		if t == nil {
+			return
+		}
+		if call, ok := operand.(*ast.CallExpr); ok && c.solutionFunc(call) != nil {
+			if compared[call] == nil {
+				compared[call] = map[int]types.Type{}
+			}
+			compared[call][0] = t
+		}
+		if ident, ok := operand.(*ast.Ident); ok {
+			if use, ok := results[c.info.Uses[ident]]; ok {
+				if compared[use.call] == nil {
+					compared[use.call] = map[int]types.Type{}
+				}
+				compared[use.call][use.index] = t
+			}
+		}
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			if c.solutionFunc(node) != nil {
+				calls = append(calls, node)
+			}
+		case *ast.AssignStmt:
+			if len(node.Rhs) != 1 {
+				return true
+			}
+			call, ok := ast.Unparen(node.Rhs[0]).(*ast.CallExpr)
+			if !ok || c.solutionFunc(call) == nil {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok {
+					if obj := c.info.Defs[ident]; obj != nil {
+						results[obj] = resultUse{call, i}
+					}
+				}
+			}
+		case *ast.BinaryExpr:
+			if node.Op == token.EQL || node.Op == token.NEQ {
+				compare(node.X, node.Y)
+				compare(node.Y, node.X)
+			}
+		}
+		return true
+	})
+
+	resultCounts := map[*ast.CallExpr]int{}
+	for _, use := range results {
+		resultCounts[use.call] = max(resultCounts[use.call], use.index+1)
+	}
+
+	var hints []string
+	for _, call := range calls {
+		fn := c.solutionFunc(call)
+		if c.reported[fn] {
+			continue
+		}
+		if hint, ok := c.checkCall(call, fn, resultCounts[call], compared[call]); ok {
+			c.reported[fn] = true
+			hints = append(hints, hint)
+		}
+	}
+	return hints
+}
+
+// checkCall compares a call with the signature of the called function. resultCount is the
+// number of variables the results are assigned to, 0 if unknown.
+func (c *signatureChecker) checkCall(call *ast.CallExpr, fn *types.Func, resultCount int, compared map[int]types.Type) (string, bool) {
+	sig := fn.Type().(*types.Signature)
+	if sig.Variadic() || call.Ellipsis.IsValid() {
+		return "", false
+	}
+
+	qualifier := types.RelativeTo(c.pkg)
+	mismatch := len(call.Args) != sig.Params().Len()
+	var params []string
+	for i, arg := range call.Args {
+		t := c.typeOf(arg)
+		if t == nil && i < sig.Params().Len() {
+			t = sig.Params().At(i).Type()
+		}
+		if t != nil && i < sig.Params().Len() && !types.AssignableTo(t, sig.Params().At(i).Type()) {
+			mismatch = true
+		}
+		params = append(params, typeString(t, qualifier))
+	}
+
+	count := sig.Results().Len()
+	if resultCount > 0 && resultCount != count {
+		mismatch = true
+		count = resultCount
+	}
+	var results []string
+	for i := range count {
+		t, ok := compared[i]
+		if ok && i < sig.Results().Len() && !comparableTypes(t, sig.Results().At(i).Type()) {
+			mismatch = true
+		}
+		if !ok && i < sig.Results().Len() {
+			t = sig.Results().At(i).Type()
+		}
+		results = append(results, typeString(t, qualifier))
+	}
+
+	if !mismatch {
+		return "", false
+	}
+	expected := fmt.Sprintf("func %s(%s)", fn.Name(), strings.Join(params, ", "))
+	switch len(results) {
+	case 0:
+	case 1:
+		expected += " " + results[0]
+	default:
+		expected += " (" + strings.Join(results, ", ") + ")"
+	}
+	declared := "func " + fn.Name() + strings.TrimPrefix(types.TypeString(sig, qualifier), "func")
+	position := c.fset.Position(call.Pos())
+	return fmt.Sprintf("The tests call `%s` with a different signature than the solution declares:\n"+
+		"  expected by the tests (%s:%d): %s\n"+
+		"  declared in the solution: %s",
+		fn.Name(), filepath.Base(position.Filename), position.Line, expected, declared), true
+}
+
+// solutionFunc returns the function of the solution that is called, nil if the call
+// is not a call of a top level function declared in a non-test file.
+func (c *signatureChecker) solutionFunc(call *ast.CallExpr) *types.Func {
+	ident, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	fn, ok := c.info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() != c.pkg || fn.Type().(*types.Signature).Recv() != nil {
+		return nil
+	}
+	if strings.HasSuffix(c.fset.Position(fn.Pos()).Filename, "_test.go") {
+		return nil
+	}
+	return fn
+}
+
+// typeOf returns the type of an expression, using the default type for untyped
+// constants. It returns nil if the type is unknown.
+func (c *signatureChecker) typeOf(expr ast.Expr) types.Type {
+	t := c.info.TypeOf(expr)
+	if t == nil || t == types.Typ[types.Invalid] || t == types.Typ[types.UntypedNil] {
+		return nil
+	}
+	if _, ok := t.(*types.Tuple); ok {
+		return nil
+	}
+	return types.Default(t)
+}
+
+// typeString returns the name of the type, "?" if it is unknown.
+func typeString(t types.Type, qualifier types.Qualifier) string {
+	if t == nil {
+		return "?"
+	}
+	return types.TypeString(t, qualifier)
+}
+
+// comparableTypes reports whether values of the types can be compared with == and !=.
+func comparableTypes(a types.Type, b types.Type) bool {
+	return types.AssignableTo(a, b) || types.AssignableTo(b, a)
+}
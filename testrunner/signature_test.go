@@ -0,0 +1,74 @@
+package testrunner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureHints(t *testing.T) {
+	tests := []struct {
+		name      string
+		solution  string
+		test      string
+		buildTags []string
+		expected  []string
+	}{
+		{
+			name:     "matching signature",
+			solution: "package leap\n\nfunc IsLeapYear(year int) bool { return year%4 == 0 }\n",
+			test:     "package leap\n\nimport \"testing\"\n\nfunc TestLeap(t *testing.T) {\n\tif !IsLeapYear(1996) {\n\t\tt.Fail()\n\t}\n}\n",
+			expected: nil,
+		},
+		{
+			name:     "missing parameter",
+			solution: "package leap\n\nfunc IsLeapYear() bool { return true }\n",
+			test:     "package leap\n\nimport \"testing\"\n\nfunc TestLeap(t *testing.T) {\n\tif got := IsLeapYear(1996); got != true {\n\t\tt.Fail()\n\t}\n}\n",
+			expected: []string{"The tests call `IsLeapYear` with a different signature than the solution declares:\n" +
+				"  expected by the tests (leap_test.go:6): func IsLeapYear(int) bool\n" +
+				"  declared in the solution: func IsLeapYear() bool"},
+		},
+		{
+			name:     "wrong result type",
+			solution: "package leap\n\nfunc IsLeapYear(year int) string { return \"\" }\n",
+			test:     "package leap\n\nimport \"testing\"\n\nfunc TestLeap(t *testing.T) {\n\twant := false\n\tif IsLeapYear(2015) != want {\n\t\tt.Fail()\n\t}\n}\n",
+			expected: []string{"The tests call `IsLeapYear` with a different signature than the solution declares:\n" +
+				"  expected by the tests (leap_test.go:7): func IsLeapYear(int) bool\n" +
+				"  declared in the solution: func IsLeapYear(year int) string"},
+		},
+		{
+			name:     "missing error result",
+			solution: "package leap\n\nfunc IsLeapYear(year int) bool { return false }\n",
+			test:     "package leap\n\nimport \"testing\"\n\nfunc TestLeap(t *testing.T) {\n\tgot, err := IsLeapYear(2015)\n\tif err != nil || got {\n\t\tt.Fail()\n\t}\n}\n",
+			expected: []string{"The tests call `IsLeapYear` with a different signature than the solution declares:\n" +
+				"  expected by the tests (leap_test.go:6): func IsLeapYear(int) (bool, error)\n" +
+				"  declared in the solution: func IsLeapYear(year int) bool"},
+		},
+		{
+			name:      "build tags",
+			solution:  "//go:build exercism\n\npackage leap\n\nfunc IsLeapYear() bool { return true }\n",
+			test:      "package leap\n\nimport \"testing\"\n\nfunc TestLeap(t *testing.T) {\n\tif !IsLeapYear(1996) {\n\t\tt.Fail()\n\t}\n}\n",
+			buildTags: []string{"exercism"},
+			expected: []string{"The tests call `IsLeapYear` with a different signature than the solution declares:\n" +
+				"  expected by the tests (leap_test.go:6): func IsLeapYear(int) bool\n" +
+				"  declared in the solution: func IsLeapYear() bool"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input_dir := t.TempDir()
+			writeFile(t, filepath.Join(input_dir, "go.mod"), "module leap\n\ngo 1.26\n")
+			writeFile(t, filepath.Join(input_dir, "leap.go"), tt.solution)
+			writeFile(t, filepath.Join(input_dir, "leap_test.go"), tt.test)
+
+			assert.Equal(t, tt.expected, signatureHints(input_dir, ExerciseConfig{BuildTags: tt.buildTags}))
+		})
+	}
+}
+
+func TestHasTestTypeErrors(t *testing.T) {
+	assert.True(t, hasTestTypeErrors("# leap [leap.test]\n./leap_test.go:6:23: too many arguments in call to IsLeapYear\n"))
+	assert.False(t, hasTestTypeErrors("# leap [leap.test]\n./leap_test.go:6:23: undefined: IsLeapYear\n"))
+	assert.False(t, hasTestTypeErrors("# leap\n./leap.go:4:2: undefined: unknownVar\n"))
+}
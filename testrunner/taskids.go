@@ -0,0 +1,142 @@
+package testrunner
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// validateTaskIDs checks the task IDs of the test results for common
+// misconfigurations. It returns a description for every problem that was found.
+// If taskCount is greater than 0, the task IDs are also compared with the
+// number of tasks of the exercise.
+// The tests are expected to be cleaned up by removeObsoleteParentTests already,
+// their names must not be prefixed with the package path yet.
+func validateTaskIDs(tests []testResult, taskCount int) []string {
+	var problems []string
+	var missing []string
+	var parents []string
+	ids := map[uint64]bool{}
+	multiple := multiplePackages(tests)
+	for _, test := range tests {
+		parentName, _ := splitTestName(test.Name)
+		if multiple {
+			// Parent tests of different packages can have the same name.
+			parentName = test.packagePath + ": " + parentName
+		}
+		if !slices.Contains(parents, parentName) {
+			parents = append(parents, parentName)
+		}
+		if test.taskIDErr != nil {
+			// The invalid annotation is reported, the test is not missing a task ID as well.
+			problem := fmt.Sprintf("%s: %s", parentName, test.taskIDErr)
+			if !slices.Contains(problems, problem) {
+				problems = append(problems, problem)
+			}
+			continue
+		}
+		if test.TaskID == 0 {
+			if !slices.Contains(missing, parentName) {
+				missing = append(missing, parentName)
+			}
+			continue
+		}
+		ids[test.TaskID] = true
+	}
+
+	if len(ids) == 0 {
+		// No explicit task IDs, so they will be auto-assigned, one per parent test.
+		if len(problems) == 0 && taskCount > 0 && len(parents) != taskCount {
+			problems = append(problems, fmt.Sprintf(
+				"auto-assigned task IDs cover %d tests but the exercise has %d tasks",
+				len(parents), taskCount,
+			))
+		}
+		return problems
+	}
+
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("task ID missing for %s", strings.Join(missing, ", ")))
+	}
+
+	sortedIDs := slices.Sorted(maps.Keys(ids))
+	maxID := sortedIDs[len(sortedIDs)-1]
+	if maxID != uint64(len(sortedIDs)) {
+		problems = append(problems, fmt.Sprintf(
+			"task IDs must be contiguous starting at 1, found %s",
+			joinTaskIDs(sortedIDs),
+		))
+	}
+
+	if taskCount > 0 && maxID != uint64(taskCount) {
+		problems = append(problems, fmt.Sprintf(
+			"highest task ID is %d but the exercise has %d tasks",
+			maxID, taskCount,
+		))
+	}
+
+	return problems
+}
+
+func joinTaskIDs(ids []uint64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprint(id))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// taskSummary aggregates the results of all tests that belong to one task.
+type taskSummary struct {
+	ID               uint64 `json:"id"`
+	Status           string `json:"status"`
+	Passed           int    `json:"passed"`
+	Failed           int    `json:"failed"`
+	Errored          int    `json:"errored"`
+	FirstFailingTest string `json:"first_failing_test,omitempty"`
+}
+
+// summarizeTasks creates one summary per task ID found in the tests, ordered by task ID.
+// A task passes if all its tests passed. If none of its tests could be executed
+// successfully, its status is "error", otherwise "fail".
+func summarizeTasks(tests []testResult) []taskSummary {
+	summaries := map[uint64]*taskSummary{}
+	for _, test := range tests {
+		if test.TaskID == 0 || test.Status != statPass && test.Status != statFail && test.Status != statErr {
+			// Skipped tests do not count, a task with only skipped tests has no summary.
+			continue
+		}
+		summary, ok := summaries[test.TaskID]
+		if !ok {
+			summary = &taskSummary{ID: test.TaskID}
+			summaries[test.TaskID] = summary
+		}
+		switch test.Status {
+		case statPass:
+			summary.Passed++
+		case statFail:
+			summary.Failed++
+		case statErr:
+			summary.Errored++
+		}
+		if test.Status != statPass && summary.FirstFailingTest == "" {
+			summary.FirstFailingTest = test.Name
+		}
+	}
+
+	result := make([]taskSummary, 0, len(summaries))
+	for _, id := range slices.Sorted(maps.Keys(summaries)) {
+		summary := summaries[id]
+		switch {
+		case summary.Failed == 0 && summary.Errored == 0:
+			summary.Status = statPass
+		case summary.Passed == 0 && summary.Failed == 0:
+			summary.Status = statErr
+		default:
+			summary.Status = statFail
+		}
+		result = append(result, *summary)
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+package testrunner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTaskIDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		tests     []testResult
+		taskCount int
+		expected  []string
+	}{
+		{
+			name: "valid explicit task ids",
+			tests: []testResult{
+				{Name: "TestA", TaskID: 1},
+				{Name: "TestB", TaskID: 2},
+				{Name: "TestC", TaskID: 2},
+			},
+			taskCount: 2,
+			expected:  nil,
+		},
+		{
+			name: "valid auto-assigned task ids",
+			tests: []testResult{
+				{Name: "TestA"},
+				{Name: "TestB"},
+			},
+			taskCount: 2,
+			expected:  nil,
+		},
+		{
+			name: "auto-assigned task ids do not match task count",
+			tests: []testResult{
+				{Name: "TestA"},
+				{Name: "TestB"},
+			},
+			taskCount: 3,
+			expected:  []string{"auto-assigned task IDs cover 2 tests but the exercise has 3 tasks"},
+		},
+		{
+			name: "partial task ids",
+			tests: []testResult{
+				{Name: "TestA", TaskID: 1},
+				{Name: "TestB"},
+				{Name: "TestC"},
+			},
+			expected: []string{"task ID missing for TestB, TestC"},
+		},
+		{
+			name: "task ids on subtests",
+			tests: []testResult{
+				{Name: "TestA/first", TaskID: 1},
+				{Name: "TestA/second", TaskID: 2},
+				{Name: "TestB", TaskID: 2},
+			},
+			taskCount: 2,
+			expected:  nil,
+		},
+		{
+			name: "partial task ids on subtests",
+			tests: []testResult{
+				{Name: "TestA/first", TaskID: 1},
+				{Name: "TestA/second"},
+				{Name: "TestA/third"},
+			},
+			expected: []string{"task ID missing for TestA"},
+		},
+		{
+			name: "auto-assigned task ids count parent tests",
+			tests: []testResult{
+				{Name: "TestA/first"},
+				{Name: "TestA/second"},
+				{Name: "TestB"},
+			},
+			taskCount: 2,
+			expected:  nil,
+		},
+		{
+			name: "non-contiguous task ids",
+			tests: []testResult{
+				{Name: "TestA", TaskID: 1},
+				{Name: "TestB", TaskID: 3},
+			},
+			expected: []string{"task IDs must be contiguous starting at 1, found 1, 3"},
+		},
+		{
+			name: "task ids do not match task count",
+			tests: []testResult{
+				{Name: "TestA", TaskID: 1},
+				{Name: "TestB", TaskID: 2},
+			},
+			taskCount: 3,
+			expected:  []string{"highest task ID is 2 but the exercise has 3 tasks"},
+		},
+		{
+			name: "invalid annotation",
+			tests: []testResult{
+				{Name: "TestA", TaskID: 1},
+				{Name: "TestB", taskIDErr: errors.New("testRunnerTaskID must be greater than 0")},
+			},
+			expected: []string{
+				"TestB: testRunnerTaskID must be greater than 0",
+			},
+		},
+		{
+			name: "parent tests with the same name in several packages",
+			tests: []testResult{
+				{Name: "TestA/first", TaskID: 1, packagePath: "leap"},
+				{Name: "TestA/first", packagePath: "leap/internal/calendar"},
+				{Name: "TestA/second", packagePath: "leap/internal/calendar"},
+			},
+			expected: []string{"task ID missing for leap/internal/calendar: TestA"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validateTaskIDs(tt.tests, tt.taskCount))
+		})
+	}
+}
+
+func TestSummarizeTasks(t *testing.T) {
+	tests := []struct {
+		name     string
+		tests    []testResult
+		expected []taskSummary
+	}{
+		{
+			name:     "no task ids",
+			tests:    []testResult{{Name: "TestA", Status: statPass}},
+			expected: []taskSummary{},
+		},
+		{
+			name: "tasks with different outcomes",
+			tests: []testResult{
+				{Name: "TestC", Status: statErr, TaskID: 3},
+				{Name: "TestA/first", Status: statPass, TaskID: 1},
+				{Name: "TestA/second", Status: statPass, TaskID: 1},
+				{Name: "TestB/first", Status: statPass, TaskID: 2},
+				{Name: "TestB/second", Status: statFail, TaskID: 2},
+				{Name: "TestB/third", Status: statErr, TaskID: 2},
+			},
+			expected: []taskSummary{
+				{ID: 1, Status: statPass, Passed: 2},
+				{ID: 2, Status: statFail, Passed: 1, Failed: 1, Errored: 1, FirstFailingTest: "TestB/second"},
+				{ID: 3, Status: statErr, Errored: 1, FirstFailingTest: "TestC"},
+			},
+		},
+		{
+			name: "skipped tests",
+			tests: []testResult{
+				{Name: "TestA", Status: statPass, TaskID: 1},
+				{Name: "TestA/skipped", Status: statSkip, TaskID: 1},
+				{Name: "TestB", Status: statSkip, TaskID: 2},
+			},
+			expected: []taskSummary{
+				{ID: 1, Status: statPass, Passed: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, summarizeTasks(tt.tests))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package conditionals
+
+// ParseCard returns the integer value of a card following blackjack ruleset.
+func ParseCard(card string) int {
+	switch card {
+	case "ace":
+		return 11
+	case "ten", "jack", "queen", "king":
+		return 10
+	case "two":
+		return 2
+	}
+	return 0
+}
+
+// IsBlackjack returns true if the player has a blackjack, false otherwise.
+func IsBlackjack(card1, card2 string) bool {
+	return ParseCard(card1)+ParseCard(card2) == 21
+}
@@ -0,0 +1,72 @@
+package conditionals
+
+import (
+	"testing"
+)
+
+// testRunnerTaskID=1
+func TestParseCard(t *testing.T) {
+	tests := []struct {
+		name string
+		card string
+		want int
+	}{
+		// This entry gets the task ID of the parent test.
+		{
+			name: "parse two",
+			card: "two",
+			want: 2,
+		},
+		// testRunnerTaskID=2
+		{
+			name: "parse jack",
+			card: "jack",
+			want: 10,
+		},
+		{
+			// testRunnerTaskID=2
+			name: "parse king",
+			card: "king",
+			want: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseCard(tt.card); got != tt.want {
+				t.Errorf("ParseCard(%s) = %d, want %d", tt.card, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlackjack(t *testing.T) {
+	tests := []struct {
+		name   string
+		taskID int
+		card1  string
+		card2  string
+		want   bool
+	}{
+		{
+			name:   "blackjack with ace first",
+			taskID: 3,
+			card1:  "ace",
+			card2:  "king",
+			want:   true,
+		},
+		{
+			name:   "no blackjack",
+			taskID: 4,
+			card1:  "two",
+			card2:  "king",
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBlackjack(tt.card1, tt.card2); got != tt.want {
+				t.Errorf("IsBlackjack(%s, %s) = %t, want %t", tt.card1, tt.card2, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+//go:build exercism
+
+package greeting
+
+// Greeting returns a greeting for the given name.
+func Greeting(name string) string {
+	return "Hello, " + name + "!"
+}
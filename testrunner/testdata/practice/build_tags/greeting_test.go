@@ -0,0 +1,9 @@
+package greeting
+
+import "testing"
+
+func TestGreeting(t *testing.T) {
+	if got := Greeting("Alice"); got != "Hello, Alice!" {
+		t.Errorf("Greeting(\"Alice\") = %q, want %q", got, "Hello, Alice!")
+	}
+}
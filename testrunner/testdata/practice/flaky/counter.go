@@ -0,0 +1,9 @@
+package counter
+
+var total int
+
+// Add adds n to the running total and returns it.
+func Add(n int) int {
+	total += n
+	return total
+}
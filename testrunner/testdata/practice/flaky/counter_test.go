@@ -0,0 +1,16 @@
+package counter
+
+import "testing"
+
+// TestAdd depends on the global total, so it fails when it runs more than once.
+func TestAdd(t *testing.T) {
+	if got := Add(2); got != 2 {
+		t.Fatalf("Add(2) = %d, want 2", got)
+	}
+}
+
+func TestAddZero(t *testing.T) {
+	if got := Add(0); got < 0 {
+		t.Fatalf("Add(0) = %d, want a non-negative total", got)
+	}
+}
@@ -0,0 +1,20 @@
+package skipped
+
+import (
+	"testing"
+)
+
+func TestDouble(t *testing.T) {
+	if got := Double(2); got != 4 {
+		t.Fatalf("Double(2) = %d, want 4", got)
+	}
+}
+
+func TestDoubleLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping large numbers in short mode.")
+	}
+	if got := Double(1 << 20); got != 1<<21 {
+		t.Fatalf("Double(1 << 20) = %d, want %d", got, 1<<21)
+	}
+}
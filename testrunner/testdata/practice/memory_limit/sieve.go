@@ -0,0 +1,17 @@
+package sieve
+
+// Sieve returns the prime numbers up to limit.
+func Sieve(limit int) []int {
+	// intentionally allocates far more memory than needed
+	composite := make([]bool, 1<<30)
+	for i := range composite {
+		composite[i] = i%2 == 0
+	}
+	var primes []int
+	for i := 2; i <= limit; i++ {
+		if !composite[i] || i == 2 {
+			primes = append(primes, i)
+		}
+	}
+	return primes
+}
@@ -0,0 +1,18 @@
+package sieve
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNoPrimeUnderTwo(t *testing.T) {
+	if got := Sieve(1); len(got) != 0 {
+		t.Errorf("Sieve(1) = %v, want []", got)
+	}
+}
+
+func TestFindFirstPrime(t *testing.T) {
+	if got := Sieve(2); !slices.Equal(got, []int{2}) {
+		t.Errorf("Sieve(2) = %v, want [2]", got)
+	}
+}
@@ -0,0 +1,15 @@
+package leap
+
+// IsLeap reports whether year is a leap year.
+func IsLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+type Calendar struct{}
+
+func (Calendar) DaysInYear(year int) int {
+	if IsLeap(year) {
+		return 366
+	}
+	return 365
+}
@@ -0,0 +1,29 @@
+package leap
+
+import "testing"
+
+func TestLeapYears(t *testing.T) {
+	tests := []struct {
+		description string
+		year        int
+		expected    bool
+	}{
+		{"year not divisible by 4", 2015, false},
+		{"year divisible by 4", 1996, true},
+		{"year divisible by 100, not by 400", 2100, false},
+		{"year divisible by 400", 2000, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if actual := IsLeapYear(tc.year); actual != tc.expected {
+				t.Fatalf("IsLeapYear(%d) = %t, want %t", tc.year, actual, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDaysInYear(t *testing.T) {
+	if actual := DaysInYear(2000); actual != 366 {
+		t.Fatalf("DaysInYear(2000) = %d, want 366", actual)
+	}
+}
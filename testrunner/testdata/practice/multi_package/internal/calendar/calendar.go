@@ -0,0 +1,9 @@
+package calendar
+
+// DaysInYear returns the number of days of year.
+func DaysInYear(year int) int {
+	if year%4 == 0 {
+		return 366
+	}
+	return 365
+}
@@ -0,0 +1,29 @@
+package calendar
+
+import "testing"
+
+func TestDaysInYear(t *testing.T) {
+	tests := []struct {
+		description string
+		year        int
+		expected    int
+	}{
+		{
+			description: "leap year",
+			year:        1996,
+			expected:    366,
+		},
+		{
+			description: "century",
+			year:        1900,
+			expected:    365,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			if actual := DaysInYear(tc.year); actual != tc.expected {
+				t.Fatalf("DaysInYear(%d) = %d, want %d", tc.year, actual, tc.expected)
+			}
+		})
+	}
+}
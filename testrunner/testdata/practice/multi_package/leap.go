@@ -0,0 +1,13 @@
+package leap
+
+import "leap/internal/calendar"
+
+// IsLeapYear reports whether year is a leap year.
+func IsLeapYear(year int) bool {
+	return calendar.DaysInYear(year) == 366
+}
+
+// DaysInYear returns the number of days of year.
+func DaysInYear(year int) int {
+	return calendar.DaysInYear(year)
+}
@@ -0,0 +1,15 @@
+package leap
+
+import "testing"
+
+func TestIsLeapYear(t *testing.T) {
+	if !IsLeapYear(1996) {
+		t.Fatal("IsLeapYear(1996) = false, want true")
+	}
+}
+
+func TestDaysInYear(t *testing.T) {
+	if actual := DaysInYear(2015); actual != 365 {
+		t.Fatalf("DaysInYear(2015) = %d, want 365", actual)
+	}
+}
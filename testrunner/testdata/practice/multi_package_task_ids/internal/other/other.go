@@ -0,0 +1,6 @@
+package other
+
+// Double returns twice the given number.
+func Double(n int) int {
+	return 2 * n
+}
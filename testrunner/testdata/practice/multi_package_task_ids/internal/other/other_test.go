@@ -0,0 +1,19 @@
+package other
+
+import "testing"
+
+func TestA(t *testing.T) {
+	t.Run("a", func(t *testing.T) {
+		if actual := Double(2); actual != 4 {
+			t.Fatalf("Double(2) = %d, want 4", actual)
+		}
+	})
+}
+
+func TestB(t *testing.T) {
+	t.Run("b", func(t *testing.T) {
+		if actual := Double(0); actual != 0 {
+			t.Fatalf("Double(0) = %d, want 0", actual)
+		}
+	})
+}
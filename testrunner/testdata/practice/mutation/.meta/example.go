@@ -0,0 +1,14 @@
+package leap
+
+// IsLeapYear reports whether the year is a leap year in the Gregorian calendar.
+func IsLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// DaysInYear returns the number of days of the year.
+func DaysInYear(year int) int {
+	if IsLeapYear(year) {
+		return 366
+	}
+	return 365
+}
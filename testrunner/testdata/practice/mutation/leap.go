@@ -0,0 +1,9 @@
+package leap
+
+func IsLeapYear(year int) bool {
+	panic("Please implement the IsLeapYear function")
+}
+
+func DaysInYear(year int) int {
+	panic("Please implement the DaysInYear function")
+}
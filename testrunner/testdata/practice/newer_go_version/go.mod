@@ -0,0 +1,5 @@
+module leap
+
+go 1.99.0
+
+toolchain go1.99.1
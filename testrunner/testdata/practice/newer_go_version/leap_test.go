@@ -0,0 +1,20 @@
+package leap
+
+import "testing"
+
+func TestIsLeapYear(t *testing.T) {
+	tests := []struct {
+		year     int
+		expected bool
+	}{
+		{1996, true},
+		{1997, false},
+		{1900, false},
+		{2000, true},
+	}
+	for _, tt := range tests {
+		if actual := IsLeapYear(tt.year); actual != tt.expected {
+			t.Errorf("IsLeapYear(%d) = %t, want %t", tt.year, actual, tt.expected)
+		}
+	}
+}
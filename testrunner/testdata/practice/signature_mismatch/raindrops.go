@@ -0,0 +1,30 @@
+package raindrops
+
+import "strconv"
+
+// Convert returns the raindrop sounds for number.
+func Convert(number float64) []string {
+	var sounds []string
+	if int(number)%3 == 0 {
+		sounds = append(sounds, "Pling")
+	}
+	if int(number)%5 == 0 {
+		sounds = append(sounds, "Plang")
+	}
+	if int(number)%7 == 0 {
+		sounds = append(sounds, "Plong")
+	}
+	if len(sounds) == 0 {
+		sounds = append(sounds, strconv.Itoa(int(number)))
+	}
+	return sounds
+}
+
+// Sounds returns the sounds of all numbers from 1 to n.
+func Sounds(n int) []string {
+	var sounds []string
+	for i := 1; i <= n; i++ {
+		sounds = append(sounds, Convert(float64(i))...)
+	}
+	return sounds
+}
@@ -0,0 +1,29 @@
+package raindrops
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{1, "1"},
+		{3, "Pling"},
+		{35, "PlangPlong"},
+	}
+	for _, tc := range tests {
+		if actual := Convert(tc.input); actual != tc.expected {
+			t.Fatalf("Convert(%d) = %q, want: %q", tc.input, actual, tc.expected)
+		}
+	}
+}
+
+func TestSounds(t *testing.T) {
+	sounds, err := Sounds(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sounds) != 3 {
+		t.Fatalf("Sounds(3) returned %d sounds, want 3", len(sounds))
+	}
+}
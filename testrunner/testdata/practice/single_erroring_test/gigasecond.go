@@ -0,0 +1,12 @@
+package gigasecond
+
+import "time"
+
+// AddGigasecond adds a gigasecond (10^9 seconds) to the input time.
+func AddGigasecond(t time.Time) time.Time {
+	if t.Year() < 2000 {
+		return t.Add(1e9 * time.Second)
+	}
+	// intentional stack overflow error
+	return AddGigasecond(t).Add(time.Second)
+}
@@ -0,0 +1,33 @@
+package gigasecond
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddGigasecond(t *testing.T) {
+	tests := []struct {
+		description string
+		input       string
+		want        string
+	}{
+		{
+			description: "date only specification of time",
+			input:       "1977-06-13",
+			want:        "2009-02-19T01:46:40Z",
+		},
+		{
+			description: "second test for date only specification of time",
+			input:       "2015-01-24",
+			want:        "2046-10-02T23:46:40Z",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			input, _ := time.Parse("2006-01-02", tc.input)
+			if got := AddGigasecond(input).Format(time.RFC3339); got != tc.want {
+				t.Fatalf("AddGigasecond(%s) = %s, want %s", tc.input, got, tc.want)
+			}
+		})
+	}
+}
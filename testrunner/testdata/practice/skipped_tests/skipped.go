@@ -0,0 +1,6 @@
+package skipped
+
+// Double returns twice the given number.
+func Double(n int) int {
+	return 2 * n
+}
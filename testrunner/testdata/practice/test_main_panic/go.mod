@@ -0,0 +1,4 @@
+module leap
+
+go 1.26
+
@@ -0,0 +1,8 @@
+package leap
+
+var divisors []int
+
+// IsLeapYear reports whether the given year is a leap year.
+func IsLeapYear(year int) bool {
+	return year%divisors[0] == 0 && (year%divisors[1] != 0 || year%divisors[2] == 0)
+}
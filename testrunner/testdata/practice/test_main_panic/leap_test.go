@@ -0,0 +1,24 @@
+package leap
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// Checking the solution before any test runs causes a panic.
+	IsLeapYear(2000)
+	os.Exit(m.Run())
+}
+
+func TestLeapYear(t *testing.T) {
+	if !IsLeapYear(2000) {
+		t.Error("IsLeapYear(2000) = false, want true")
+	}
+}
+
+func TestNonLeapYear(t *testing.T) {
+	if IsLeapYear(1900) {
+		t.Error("IsLeapYear(1900) = true, want false")
+	}
+}
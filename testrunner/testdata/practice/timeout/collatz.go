@@ -0,0 +1,16 @@
+package collatz
+
+// Steps returns the number of steps it takes to reach 1.
+func Steps(n int) int {
+	steps := 0
+	for n != 1 {
+		if n%2 == 0 {
+			n /= 2
+		} else {
+			// Should be 3*n + 1, this never terminates for n = 7.
+			n = 3*n - 1
+		}
+		steps++
+	}
+	return steps
+}
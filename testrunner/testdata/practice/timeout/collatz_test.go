@@ -0,0 +1,21 @@
+package collatz
+
+import "testing"
+
+func TestOne(t *testing.T) {
+	if got := Steps(1); got != 0 {
+		t.Errorf("Steps(1) = %d, want 0", got)
+	}
+}
+
+func TestSeven(t *testing.T) {
+	if got := Steps(7); got != 16 {
+		t.Errorf("Steps(7) = %d, want 16", got)
+	}
+}
+
+func TestFour(t *testing.T) {
+	if got := Steps(4); got != 2 {
+		t.Errorf("Steps(4) = %d, want 2", got)
+	}
+}
@@ -0,0 +1,5 @@
+module greeting
+
+go 1.26
+
+require example.com/greetings v1.2.3
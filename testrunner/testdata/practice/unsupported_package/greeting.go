@@ -0,0 +1,11 @@
+package greeting
+
+import (
+	"example.com/greetings"
+	"github.com/example/emoji"
+)
+
+// HelloWorld returns a greeting.
+func HelloWorld() string {
+	return greetings.Hello("World") + emoji.Wave
+}
@@ -0,0 +1,9 @@
+package greeting
+
+import "testing"
+
+func TestHelloWorld(t *testing.T) {
+	if actual := HelloWorld(); actual != "Hello, World! 👋" {
+		t.Fatalf("HelloWorld() = %q, want %q", actual, "Hello, World! 👋")
+	}
+}
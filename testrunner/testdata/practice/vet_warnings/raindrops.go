@@ -0,0 +1,33 @@
+package raindrops
+
+import (
+	"fmt"
+	"strconv"
+)
+
+func Convert(number int) string {
+	result := ""
+	for _, sound := range []struct {
+		factor int
+		sound  string
+	}{{3, "Pling"}, {5, "Plang"}, {7, "Plong"}} {
+		if number%sound.factor == 0 {
+			result := result + sound.sound
+			result = result
+		}
+	}
+	if number%3 == 0 {
+		result += "Pling"
+	}
+	if number%5 == 0 {
+		result += "Plang"
+	}
+	if number%7 == 0 {
+		result += "Plong"
+	}
+	fmt.Sprintf("%d", number)
+	if result == "" {
+		return strconv.Itoa(number)
+	}
+	return result
+}
@@ -0,0 +1,19 @@
+package raindrops
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{3, "Pling"},
+		{35, "PlangPlong"},
+		{105, "PlingPlangPlong"},
+	}
+	for _, tc := range tests {
+		if actual := Convert(tc.input); actual != tc.expected {
+			t.Errorf("Convert(%d) = %q, want: %q", tc.input, actual, tc.expected)
+		}
+	}
+}